@@ -0,0 +1,93 @@
+package jsonschema
+
+import "strings"
+
+// BestMatch returns the leaf error of the tree rooted at ve, which is most
+// relevant to show to the user, for example for a single typo in a document
+// validated against "anyOf" schemas.
+//
+// Starting at ve, it descends into the most relevant cause, which is the
+// first by the following rules:
+//   - branches, whose "type" matched the value
+//   - errors of keywords other than "anyOf" and "oneOf"
+//   - errors with deepest InstancePtr among its leaf errors
+//   - errors with fewest leaf errors
+func (ve *ValidationError) BestMatch() *ValidationError {
+	for len(ve.Causes) > 0 {
+		ve = ve.bestCause()
+	}
+	return ve
+}
+
+// Pruned returns a copy of the tree rooted at ve, in which the errors of
+// "anyOf" and "oneOf" keep only the cause of the most relevant branch, as
+// chosen by BestMatch.
+func (ve *ValidationError) Pruned() *ValidationError {
+	pruned := *ve
+	causes := ve.Causes
+	if (ve.Keyword == "anyOf" || ve.Keyword == "oneOf") && len(causes) > 1 {
+		causes = []*ValidationError{ve.bestCause()}
+	}
+	pruned.Causes = make([]*ValidationError, len(causes))
+	for i, cause := range causes {
+		pruned.Causes[i] = cause.Pruned()
+	}
+	return &pruned
+}
+
+func (ve *ValidationError) bestCause() *ValidationError {
+	best, bestRank := ve.Causes[0], ve.rank(ve.Causes[0])
+	for _, cause := range ve.Causes[1:] {
+		if r := ve.rank(cause); r.less(bestRank) {
+			best, bestRank = cause, r
+		}
+	}
+	return best
+}
+
+// relevance ranks the causes of an error. The most relevant cause is the
+// least.
+type relevance struct {
+	typeMismatch bool // "type" failed for the value of the parent error.
+	weak         bool // error of "anyOf" or "oneOf".
+	depth        int  // negated depth of deepest leaf error.
+	leaves       int  // number of leaf errors.
+
+	// for stable choice among equally relevant causes.
+	instancePtr, schemaPtr string
+}
+
+func (ve *ValidationError) rank(cause *ValidationError) relevance {
+	r := relevance{
+		weak:        cause.Keyword == "anyOf" || cause.Keyword == "oneOf",
+		instancePtr: cause.InstancePtr,
+		schemaPtr:   cause.SchemaURL + cause.SchemaPtr,
+	}
+	for _, leaf := range cause.Leaves() {
+		r.leaves++
+		if depth := strings.Count(leaf.InstancePtr, "/"); -depth < r.depth {
+			r.depth = -depth
+		}
+		if leaf.Keyword == "type" && leaf.InstancePtr == ve.InstancePtr {
+			r.typeMismatch = true
+		}
+	}
+	return r
+}
+
+func (r relevance) less(other relevance) bool {
+	switch {
+	case r.typeMismatch != other.typeMismatch:
+		return !r.typeMismatch
+	case r.weak != other.weak:
+		return !r.weak
+	case r.depth != other.depth:
+		return r.depth < other.depth
+	case r.leaves != other.leaves:
+		return r.leaves < other.leaves
+	case r.instancePtr != other.instancePtr:
+		return r.instancePtr < other.instancePtr
+	default:
+		return r.schemaPtr < other.schemaPtr
+	}
+}
@@ -0,0 +1,73 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/jsonschematest"
+)
+
+func TestBestMatch(t *testing.T) {
+	schema, err := jsonschema.CompileString(ctx, "test.json", `{
+		"properties": {
+			"contact": {
+				"anyOf": [
+					{"type": "string", "format": "email"},
+					{"type": "object", "required": ["phone"], "properties": {"phone": {"type": "string", "pattern": "^[0-9]+$"}}},
+					{"type": "array"}
+				]
+			},
+			"id": {"type": "integer"}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		doc       string
+		schemaPtr string
+	}{
+		{`{"contact": "alice"}`, "#/properties/contact/anyOf/0/format"},
+		{`{"contact": {"phon": "1"}}`, "#/properties/contact/anyOf/1/required"},
+		{`{"contact": {"phone": "x1"}}`, "#/properties/contact/anyOf/1/properties/phone/pattern"},
+		{`{"contact": 1}`, "#/properties/contact/anyOf/0/type"},
+		{`{"contact": 1, "id": "1"}`, "#/properties/id/type"},
+	} {
+		verr := jsonschematest.AssertInvalid(t, schema, tc.doc)
+		if verr == nil {
+			continue
+		}
+		for i := 0; i < 10; i++ {
+			if got := verr.BestMatch().SchemaPtr; got != tc.schemaPtr {
+				t.Errorf("%s: expected best match %s, got %s", tc.doc, tc.schemaPtr, got)
+				break
+			}
+		}
+	}
+}
+
+func TestPruned(t *testing.T) {
+	schema, err := jsonschema.CompileString(ctx, "test.json", `{
+		"oneOf": [
+			{"type": "string"},
+			{"type": "object", "properties": {"a": {"anyOf": [{"type": "string"}, {"type": "integer", "maximum": 0}]}}}
+		]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verr := jsonschematest.AssertInvalid(t, schema, `{"a": 5}`)
+	if verr == nil {
+		t.FailNow()
+	}
+	want := `I[#] S[test.json#/oneOf] oneOf failed
+  I[#/a] S[test.json#/oneOf/1/properties/a/anyOf] anyOf failed
+    I[#/a] S[test.json#/oneOf/1/properties/a/anyOf/1/maximum] must be <= 0 but found 5
+`
+	if got := jsonschematest.Render(verr.Pruned()); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if len(verr.Causes) != 2 {
+		t.Errorf("Pruned must not modify the error, got %d causes", len(verr.Causes))
+	}
+}
@@ -0,0 +1,200 @@
+package jsonschema
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ory/jsonschema/v3/internal/jsonvalue"
+)
+
+// Bundle returns a single json document equivalent to the json-schema at given
+// url, in which all external schemas referred by "$ref" are embedded under
+// "definitions".
+//
+// All "$ref"s of the returned document are json-pointers into the document
+// itself, so it can be used without access to the referenced urls. The urls are
+// loaded the same way as by Compile.
+func (c *Compiler) Bundle(ctx context.Context, url string) (interface{}, error) {
+	base, _ := split(url)
+	r, err := c.resource(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	b := &bundler{locator: newLocator(c), root: r, keys: make(map[string]string)}
+	doc, err := b.rewrite(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if len(b.embedded) == 0 {
+		return doc, nil
+	}
+
+	m := doc.(map[string]interface{})
+	definitions, _ := m["definitions"].(map[string]interface{})
+	if definitions == nil {
+		definitions = make(map[string]interface{})
+		m["definitions"] = definitions
+	}
+	// embedded resources may refer to further resources, which are appended
+	// to b.embedded while rewriting.
+	for i := 0; i < len(b.embedded); i++ {
+		er := b.embedded[i]
+		edoc, err := b.rewrite(ctx, er)
+		if err != nil {
+			return nil, err
+		}
+		if em, ok := edoc.(map[string]interface{}); ok {
+			delete(em, "$schema")
+		}
+		definitions[b.keys[er.url]] = edoc
+	}
+	return doc, nil
+}
+
+type bundler struct {
+	*locator
+	root     *resource
+	embedded []*resource
+	keys     map[string]string // definitions key of embedded resource, by url.
+}
+
+// rewrite returns a copy of the document of r, where all "$ref"s are replaced
+// by json-pointers into the bundle, and all ids except that of the bundle are
+// removed.
+func (b *bundler) rewrite(ctx context.Context, r *resource) (interface{}, error) {
+	if r.draft != b.root.draft {
+		return nil, fmt.Errorf("cannot bundle %q into %q: drafts differ", r.url, b.root.url)
+	}
+	doc := jsonvalue.Copy(r.doc)
+	err := walkSchemas(r.draft, r.url, "", doc, func(base, ptr string, m map[string]interface{}) error {
+		if ptr != "" || r != b.root {
+			delete(m, r.draft.id)
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return nil
+		}
+		target, targetPtr, err := b.resolveRef(ctx, r, base, ref)
+		if err != nil {
+			return err
+		}
+		if target == b.root {
+			m["$ref"] = "#" + targetPtr
+		} else {
+			m["$ref"] = "#/definitions/" + escape(b.embed(target)) + targetPtr
+		}
+		return nil
+	})
+	return doc, err
+}
+
+// locator finds the schemas referred by "$ref" in the documents of a Compiler.
+type locator struct {
+	c       *Compiler
+	indexes map[string]map[string]string // json-pointer of schema by id, by resource url.
+}
+
+func newLocator(c *Compiler) *locator {
+	return &locator{c: c, indexes: make(map[string]map[string]string)}
+}
+
+// resolveRef returns the resource and the json-pointer within, of the schema
+// referred by ref from resource r. base is the url against which ref is resolved.
+func (l *locator) resolveRef(ctx context.Context, r *resource, base, ref string) (*resource, string, error) {
+	// like Compiler.compileRef, json-pointers are resolved against the resource
+	// ignoring ids of enclosing schemas.
+	if rootFragment(ref) {
+		return r, "", nil
+	}
+	if strings.HasPrefix(ref, "#/") {
+		return r, strings.TrimPrefix(ref, "#"), nil
+	}
+	refURL, err := resolveURL(base, ref)
+	if err != nil {
+		return nil, "", err
+	}
+	return l.locate(ctx, r, normalize(refURL))
+}
+
+// locate returns the resource and the json-pointer within, of the schema
+// referred by refURL from resource r.
+func (l *locator) locate(ctx context.Context, r *resource, refURL string) (*resource, string, error) {
+	if ptr, ok := l.index(r)[refURL]; ok {
+		return r, ptr, nil
+	}
+	docURL, fragment := split(refURL)
+	target := r
+	if docURL != r.url {
+		var err error
+		if target, err = l.c.resource(ctx, docURL); err != nil {
+			return nil, "", err
+		}
+		if ptr, ok := l.index(target)[refURL]; ok {
+			return target, ptr, nil
+		}
+	}
+	if rootFragment(fragment) {
+		return target, "", nil
+	}
+	if !strings.HasPrefix(fragment, "#/") {
+		return nil, "", fmt.Errorf("invalid ref: %q", refURL)
+	}
+	return target, strings.TrimPrefix(fragment, "#"), nil
+}
+
+// index returns json-pointers of the schemas with id in resource r, by their
+// absolute url.
+func (l *locator) index(r *resource) map[string]string {
+	if idx, ok := l.indexes[r.url]; ok {
+		return idx
+	}
+	idx := map[string]string{normalize(r.url): ""}
+	_ = walkSchemas(r.draft, r.url, "", r.doc, func(base, ptr string, m map[string]interface{}) error {
+		if _, ok := m[r.draft.id]; ok {
+			idx[normalize(base)] = ptr
+		}
+		return nil
+	})
+	l.indexes[r.url] = idx
+	return idx
+}
+
+var nonKeyChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// embed schedules resource r for embedding, and returns its definitions key.
+func (b *bundler) embed(r *resource) string {
+	if key, ok := b.keys[r.url]; ok {
+		return key
+	}
+	name := strings.TrimSuffix(path.Base(strings.TrimRight(r.url, "/")), ".json")
+	name = nonKeyChars.ReplaceAllString(name, "_")
+	if name == "" || name == "." {
+		name = "schema"
+	}
+	taken := func(key string) bool {
+		if m, ok := b.root.doc.(map[string]interface{}); ok {
+			if defs, ok := m["definitions"].(map[string]interface{}); ok {
+				if _, ok := defs[key]; ok {
+					return true
+				}
+			}
+		}
+		for _, k := range b.keys {
+			if k == key {
+				return true
+			}
+		}
+		return false
+	}
+	key := name
+	for i := 2; taken(key); i++ {
+		key = name + strconv.Itoa(i)
+	}
+	b.keys[r.url] = key
+	b.embedded = append(b.embedded, r)
+	return key
+}
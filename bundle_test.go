@@ -0,0 +1,124 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+func TestBundle(t *testing.T) {
+	files := map[string]string{
+		"http://example.com/user.json":        "testdata/bundle/user.json",
+		"http://example.com/address.json":     "testdata/bundle/address.json",
+		"http://example.com/common/name.json": "testdata/bundle/common/name.json",
+	}
+	c := jsonschema.NewCompiler()
+	c.LoadURL = func(ctx context.Context, s string) (io.ReadCloser, error) {
+		if f, ok := files[s]; ok {
+			return os.Open(f)
+		}
+		return nil, errors.New("unexpected url " + s)
+	}
+	doc, err := c.Bundle(ctx, "http://example.com/user.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(string(data))
+
+	m := doc.(map[string]interface{})
+	for ptr, want := range map[string]string{
+		"properties/name":          "#/definitions/name2",
+		"properties/address":       "#/definitions/address/definitions/address",
+		"properties/friends/items": "#",
+		"properties/email":         "#/definitions/email",
+		"definitions/address/definitions/address/properties/street":  "#/definitions/name2",
+		"definitions/address/definitions/address/properties/country": "#/definitions/address/definitions/country",
+	} {
+		v := interface{}(m)
+		for _, token := range strings.Split(ptr, "/") {
+			v = v.(map[string]interface{})[token]
+		}
+		if got := v.(map[string]interface{})["$ref"]; got != want {
+			t.Errorf("%s: got $ref %v, want %s", ptr, got, want)
+		}
+	}
+	if _, ok := m["definitions"].(map[string]interface{})["address"].(map[string]interface{})["definitions"].(map[string]interface{})["country"].(map[string]interface{})["$id"]; ok {
+		t.Error("ids of embedded schemas must be removed")
+	}
+
+	// the bundle must compile without access to any of the files.
+	bc := jsonschema.NewCompiler()
+	bc.LoadURL = func(ctx context.Context, s string) (io.ReadCloser, error) {
+		return nil, errors.New("unexpected url " + s)
+	}
+	if err := bc.AddResource("bundle.json", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	s, err := bc.Compile(ctx, "bundle.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for doc, valid := range map[string]bool{
+		`{"name": "foo", "address": {"street": "bar", "country": "DE"}, "friends": [{"name": "baz"}]}`: true,
+		`{"name": "foobar"}`:                              false,
+		`{"address": {"street": "bar", "country": "FR"}}`: false,
+		`{"address": {}}`:                                 false,
+		`{"friends": [{"name": 1}]}`:                      false,
+		`{"email": "not an email"}`:                       false,
+	} {
+		if err := s.Validate(strings.NewReader(doc)); (err == nil) != valid {
+			t.Errorf("%s: expected valid=%t, got %v", doc, valid, err)
+		}
+	}
+}
+
+func TestBundleDraftMismatch(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("a.json", strings.NewReader(`{"$ref": "b.json"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddResource("b.json", strings.NewReader(`{"$schema": "http://json-schema.org/draft-04/schema#"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Bundle(ctx, "a.json"); err == nil {
+		t.Error("error expected")
+	}
+}
+
+func TestBundleFragmentRefInSubschemaWithID(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	c.LoadURL = func(ctx context.Context, s string) (io.ReadCloser, error) {
+		return nil, errors.New("unexpected url " + s)
+	}
+	// json-pointer refs are resolved against the document, not against the
+	// id of the enclosing schema, as by Compile.
+	if err := c.AddResource("http://example.com/root.json", strings.NewReader(`{
+		"properties": {
+			"item": {"$id": "item.json", "$ref": "#/definitions/positive"}
+		},
+		"definitions": {
+			"positive": {"minimum": 0}
+		}
+	}`)); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := c.Bundle(ctx, "http://example.com/root.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := doc.(map[string]interface{})["properties"].(map[string]interface{})["item"].(map[string]interface{})
+	if got, want := item["$ref"], "#/definitions/positive"; got != want {
+		t.Errorf("got $ref %v, want %s", got, want)
+	}
+}
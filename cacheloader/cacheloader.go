@@ -0,0 +1,294 @@
+// Package cacheloader implements loader middleware which caches the documents
+// returned by another loader, and a loader which serves remote urls from a
+// local mirror directory.
+//
+// Both are composed around the loader function signature used by
+// jsonschema.Loaders and Compiler.LoadURL:
+//
+//	cache := &cacheloader.Cache{Next: httploader.Load, TTL: time.Hour, Dir: ".cache/schemas"}
+//	compiler := jsonschema.NewCompiler()
+//	compiler.LoadURL = cache.Load
+//
+// To build without network, map the remote urls to a local directory:
+//
+//	mirror := &cacheloader.Mirror{Prefixes: map[string]string{
+//		"https://schemas.example.com/": "testdata/schemas",
+//	}}
+//	compiler.LoadURL = mirror.Load
+package cacheloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotModified is returned by loaders supporting revalidation, when the
+// document has not changed since the entity tag returned by IfNoneMatch.
+var ErrNotModified = errors.New("document not modified")
+
+type key string
+
+const ifNoneMatchKey key = "github.com/ory/jsonschema/v3/cacheloader.IfNoneMatch"
+
+// IfNoneMatch returns the entity tag of the cached document, which the Cache
+// is revalidating.
+//
+// Loaders supporting revalidation should return ErrNotModified, if the document
+// still matches the entity tag. They also should return an io.ReadCloser
+// implementing ETag() string, so that the entity tag gets cached.
+func IfNoneMatch(ctx context.Context) string {
+	etag, _ := ctx.Value(ifNoneMatchKey).(string)
+	return etag
+}
+
+// Cache is loader middleware which caches the documents loaded by Next,
+// keyed by url.
+type Cache struct {
+	// Next loads the documents missing in the cache. It is required, and
+	// must not load using the Cache.
+	Next func(ctx context.Context, url string) (io.ReadCloser, error)
+
+	// TTL is the duration after which the cached document is revalidated
+	// using Next. Zero means the cached documents never expire.
+	TTL time.Duration
+
+	// Dir is the directory where the cached documents are persisted.
+	// If empty, the documents are cached only in memory.
+	Dir string
+
+	// StaleIfError tells whether an expired document is used, when
+	// revalidating it fails.
+	StaleIfError bool
+
+	mu       sync.Mutex // guards entries and fetching.
+	entries  map[string]*entry
+	fetching map[string]*fetchCall
+	now      func() time.Time
+}
+
+// fetchCall is an in-flight fetch, shared by concurrent loads of its url.
+type fetchCall struct {
+	done chan struct{}
+	e    *entry
+	err  error
+}
+
+type entry struct {
+	URL     string    `json:"url"`
+	ETag    string    `json:"etag,omitempty"`
+	Fetched time.Time `json:"fetched"`
+	data    []byte
+}
+
+// Load loads the document at url from the cache, or using Next. It has the
+// function signature used by jsonschema.Loaders and Compiler.LoadURL.
+//
+// Concurrent loads of a url, which is not cached, share a single fetch. A
+// canceled caller stops waiting for it, but the fetch continues for the
+// others and its document is cached.
+func (c *Cache) Load(ctx context.Context, url string) (io.ReadCloser, error) {
+	if c.Next == nil {
+		return nil, errors.New("cacheloader: Cache.Next is not set")
+	}
+
+	c.mu.Lock()
+	e, err := c.lookup(url)
+	if err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	if e != nil && (c.TTL == 0 || c.clock().Sub(e.Fetched) < c.TTL) {
+		c.mu.Unlock()
+		return io.NopCloser(bytes.NewReader(e.data)), nil
+	}
+	call, ok := c.fetching[url]
+	if !ok {
+		call = &fetchCall{done: make(chan struct{})}
+		if c.fetching == nil {
+			c.fetching = make(map[string]*fetchCall)
+		}
+		c.fetching[url] = call
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		// the fetch is shared, so it must not fail, when the caller starting
+		// it is canceled.
+		go c.run(context.WithoutCancel(ctx), call, url, e)
+	}
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if call.err != nil {
+		if e != nil && c.StaleIfError {
+			return io.NopCloser(bytes.NewReader(e.data)), nil
+		}
+		return nil, call.err
+	}
+	return io.NopCloser(bytes.NewReader(call.e.data)), nil
+}
+
+// run performs the shared fetch call of url, and caches its document.
+func (c *Cache) run(ctx context.Context, call *fetchCall, url string, stale *entry) {
+	call.e, call.err = c.fetch(ctx, url, stale)
+	c.mu.Lock()
+	delete(c.fetching, url)
+	if call.err == nil {
+		call.err = c.store(call.e)
+	}
+	c.mu.Unlock()
+	close(call.done)
+}
+
+func (c *Cache) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// fetch loads url using Next. If stale is not nil, it is revalidated and
+// returned with updated fetch time, when Next reports ErrNotModified.
+func (c *Cache) fetch(ctx context.Context, url string, stale *entry) (*entry, error) {
+	if stale != nil && stale.ETag != "" {
+		ctx = context.WithValue(ctx, ifNoneMatchKey, stale.ETag)
+	}
+	r, err := c.Next(ctx, url)
+	if errors.Is(err, ErrNotModified) && stale != nil {
+		return &entry{URL: url, ETag: stale.ETag, Fetched: c.clock(), data: stale.data}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	e := &entry{URL: url, Fetched: c.clock(), data: data}
+	if r, ok := r.(interface{ ETag() string }); ok {
+		e.ETag = r.ETag()
+	}
+	return e, nil
+}
+
+func (c *Cache) lookup(url string) (*entry, error) {
+	if e, ok := c.entries[url]; ok {
+		return e, nil
+	}
+	if c.Dir == "" {
+		return nil, nil
+	}
+	name := c.filename(url)
+	meta, err := os.ReadFile(name + ".meta.json")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	e := new(entry)
+	if err := json.Unmarshal(meta, e); err != nil {
+		return nil, fmt.Errorf("reading cache entry for %q failed. Reason: %v", url, err)
+	}
+	if e.data, err = os.ReadFile(name + ".json"); err != nil {
+		return nil, err
+	}
+	c.remember(e)
+	return e, nil
+}
+
+func (c *Cache) store(e *entry) error {
+	c.remember(e)
+	if c.Dir == "" {
+		return nil
+	}
+	meta, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.Dir, 0o750); err != nil {
+		return err
+	}
+	name := c.filename(e.URL)
+	if err := os.WriteFile(name+".json", e.data, 0o600); err != nil {
+		return err
+	}
+	return os.WriteFile(name+".meta.json", meta, 0o600)
+}
+
+func (c *Cache) remember(e *entry) {
+	if c.entries == nil {
+		c.entries = make(map[string]*entry)
+	}
+	c.entries[e.URL] = e
+}
+
+func (c *Cache) filename(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:]))
+}
+
+// NotMirroredError is the error type returned by Mirror, when the url does
+// not match any of its prefixes and no Next loader is set.
+type NotMirroredError string
+
+func (e NotMirroredError) Error() string {
+	return fmt.Sprintf("no mirror configured for url %s", string(e))
+}
+
+// Mirror is a loader which maps url prefixes to local directories.
+type Mirror struct {
+	// Prefixes maps url prefixes, such as "https://schemas.example.com/",
+	// to the local directories mirroring them.
+	Prefixes map[string]string
+
+	// Next loads the urls not matching any prefix.
+	//
+	// If nil, the mirror works offline and such urls fail to load with
+	// NotMirroredError.
+	Next func(ctx context.Context, url string) (io.ReadCloser, error)
+}
+
+// Load loads the document at url from the local directory of its prefix. It
+// has the function signature used by jsonschema.Loaders and Compiler.LoadURL.
+func (m *Mirror) Load(ctx context.Context, url string) (io.ReadCloser, error) {
+	prefixes := make([]string, 0, len(m.Prefixes))
+	for prefix := range m.Prefixes {
+		prefixes = append(prefixes, prefix)
+	}
+	// the longest prefix wins.
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+
+	for _, prefix := range prefixes {
+		if !strings.HasPrefix(url, prefix) {
+			continue
+		}
+		dir := m.Prefixes[prefix]
+		rel := filepath.FromSlash(strings.TrimPrefix(url, prefix))
+		name := filepath.Join(dir, rel)
+		if r, err := filepath.Rel(dir, name); err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("url %s escapes mirror directory %s", url, dir)
+		}
+		return os.Open(name)
+	}
+
+	if m.Next == nil {
+		return nil, NotMirroredError(url)
+	}
+	return m.Next(ctx, url)
+}
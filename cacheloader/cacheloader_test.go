@@ -0,0 +1,221 @@
+package cacheloader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type origin struct {
+	doc   string
+	etag  string
+	err   error
+	calls int
+	seen  []string
+}
+
+type etagBody struct {
+	io.ReadCloser
+	etag string
+}
+
+func (b *etagBody) ETag() string { return b.etag }
+
+func (o *origin) load(ctx context.Context, url string) (io.ReadCloser, error) {
+	o.calls++
+	o.seen = append(o.seen, IfNoneMatch(ctx))
+	if o.err != nil {
+		return nil, o.err
+	}
+	if o.etag != "" && IfNoneMatch(ctx) == o.etag {
+		return nil, ErrNotModified
+	}
+	return &etagBody{io.NopCloser(strings.NewReader(o.doc)), o.etag}, nil
+}
+
+func reader(t *testing.T) func(io.ReadCloser, error) string {
+	return func(r io.ReadCloser, err error) string {
+		t.Helper()
+		require.NoError(t, err)
+		defer r.Close()
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		return string(b)
+	}
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	const url = "https://schemas.example.com/user.json"
+
+	t.Run("case=memory", func(t *testing.T) {
+		read := reader(t)
+		o := &origin{doc: `{"type":"string"}`}
+		c := &Cache{Next: o.load}
+		assert.Equal(t, o.doc, read(c.Load(ctx, url)))
+		assert.Equal(t, o.doc, read(c.Load(ctx, url)))
+		assert.Equal(t, 1, o.calls)
+	})
+
+	t.Run("case=ttl and revalidation", func(t *testing.T) {
+		read := reader(t)
+		now := time.Now()
+		o := &origin{doc: `{"type":"string"}`, etag: `"v1"`}
+		c := &Cache{Next: o.load, TTL: time.Minute, now: func() time.Time { return now }}
+		assert.Equal(t, o.doc, read(c.Load(ctx, url)))
+
+		now = now.Add(30 * time.Second)
+		assert.Equal(t, o.doc, read(c.Load(ctx, url)))
+		assert.Equal(t, 1, o.calls)
+
+		now = now.Add(time.Minute)
+		assert.Equal(t, o.doc, read(c.Load(ctx, url)))
+		assert.Equal(t, []string{"", `"v1"`}, o.seen)
+
+		o.doc, o.etag = `{"type":"integer"}`, `"v2"`
+		now = now.Add(2 * time.Minute)
+		assert.Equal(t, o.doc, read(c.Load(ctx, url)))
+		assert.Equal(t, 3, o.calls)
+	})
+
+	t.Run("case=stale if error", func(t *testing.T) {
+		read := reader(t)
+		now := time.Now()
+		o := &origin{doc: `{}`}
+		c := &Cache{Next: o.load, TTL: time.Minute, now: func() time.Time { return now }}
+		assert.Equal(t, o.doc, read(c.Load(ctx, url)))
+
+		o.err = errors.New("offline")
+		now = now.Add(2 * time.Minute)
+		_, err := c.Load(ctx, url)
+		assert.ErrorIs(t, err, o.err)
+
+		c.StaleIfError = true
+		assert.Equal(t, o.doc, read(c.Load(ctx, url)))
+	})
+
+	t.Run("case=concurrent", func(t *testing.T) {
+		read := reader(t)
+		started, release := make(chan struct{}), make(chan struct{})
+		var calls int32
+		c := &Cache{Next: func(ctx context.Context, url string) (io.ReadCloser, error) {
+			atomic.AddInt32(&calls, 1)
+			if strings.HasSuffix(url, "slow.json") {
+				close(started)
+				<-release
+			}
+			return io.NopCloser(strings.NewReader(`{}`)), nil
+		}}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r, err := c.Load(ctx, "https://schemas.example.com/slow.json")
+				if assert.NoError(t, err) {
+					r.Close()
+				}
+			}()
+		}
+
+		// other urls load, while slow.json is fetched.
+		<-started
+		assert.Equal(t, `{}`, read(c.Load(ctx, url)))
+		close(release)
+		wg.Wait()
+		assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("case=canceled caller", func(t *testing.T) {
+		read := reader(t)
+		started, release := make(chan struct{}), make(chan struct{})
+		var calls int32
+		c := &Cache{Next: func(ctx context.Context, url string) (io.ReadCloser, error) {
+			atomic.AddInt32(&calls, 1)
+			close(started)
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			return io.NopCloser(strings.NewReader(`{}`)), nil
+		}}
+
+		// the first caller starts the fetch and is canceled, while another
+		// caller waits for it.
+		first, cancel := context.WithCancel(ctx)
+		canceled := make(chan error)
+		go func() {
+			_, err := c.Load(first, url)
+			canceled <- err
+		}()
+		<-started
+		waited := make(chan io.ReadCloser)
+		go func() {
+			r, err := c.Load(ctx, url)
+			assert.NoError(t, err)
+			waited <- r
+		}()
+		cancel()
+		assert.ErrorIs(t, <-canceled, context.Canceled)
+
+		close(release)
+		assert.Equal(t, `{}`, read(<-waited, nil))
+		assert.Equal(t, `{}`, read(c.Load(ctx, url)))
+		assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("case=no next", func(t *testing.T) {
+		_, err := (&Cache{}).Load(ctx, url)
+		assert.EqualError(t, err, "cacheloader: Cache.Next is not set")
+	})
+
+	t.Run("case=disk", func(t *testing.T) {
+		read := reader(t)
+		dir := t.TempDir()
+		o := &origin{doc: `{"type":"string"}`, etag: `"v1"`}
+		assert.Equal(t, o.doc, read((&Cache{Next: o.load, Dir: dir}).Load(ctx, url)))
+
+		o.err = errors.New("offline")
+		assert.Equal(t, `{"type":"string"}`, read((&Cache{Next: o.load, Dir: dir}).Load(ctx, url)))
+		assert.Equal(t, 1, o.calls)
+	})
+}
+
+func TestMirror(t *testing.T) {
+	read := reader(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "v1"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "v1", "user.json"), []byte(`{"type":"object"}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "user.json"), []byte(`{"type":"string"}`), 0o600))
+
+	m := &Mirror{Prefixes: map[string]string{
+		"https://schemas.example.com/":    dir,
+		"https://schemas.example.com/v2/": filepath.Join(dir, "v1"),
+	}}
+	assert.Equal(t, `{"type":"object"}`, read(m.Load(ctx, "https://schemas.example.com/v1/user.json")))
+	assert.Equal(t, `{"type":"object"}`, read(m.Load(ctx, "https://schemas.example.com/v2/user.json")))
+	assert.Equal(t, `{"type":"string"}`, read(m.Load(ctx, "https://schemas.example.com/user.json")))
+
+	_, err := m.Load(ctx, "https://schemas.example.com/../secret.json")
+	assert.Error(t, err)
+
+	_, err = m.Load(ctx, "https://other.example.com/user.json")
+	assert.ErrorIs(t, err, NotMirroredError("https://other.example.com/user.json"))
+
+	o := &origin{doc: `{}`}
+	m.Next = o.load
+	assert.Equal(t, `{}`, read(m.Load(ctx, "https://other.example.com/user.json")))
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+// compat prints the changes from the old json-schema to the new one. The exit
+// code is 1, if the change does not have the required compatibility.
+func compat(ctx context.Context, args []string) int {
+	flags := flag.NewFlagSet("compat", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "jv compat [-require backward|forward|full|breaking] <old-json-schema> <new-json-schema>")
+		flags.PrintDefaults()
+	}
+	require := flags.String("require", string(jsonschema.CompatBackward), "compatibility required for exit code 0")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 2 {
+		if err == nil {
+			flags.Usage()
+		}
+		return 1
+	}
+	switch jsonschema.Compatibility(*require) {
+	case jsonschema.CompatFull, jsonschema.CompatBackward, jsonschema.CompatForward, jsonschema.CompatBreaking:
+	default:
+		fmt.Fprintf(os.Stderr, "invalid compatibility %q\n", *require)
+		return 1
+	}
+
+	oldSchema, err := jsonschema.Compile(ctx, flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	newSchema, err := jsonschema.Compile(ctx, flags.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	report := jsonschema.CheckCompatibility(oldSchema, newSchema)
+	for _, change := range report.Changes {
+		fmt.Println(change)
+	}
+	fmt.Println("compatibility:", report.Compatibility)
+	if !report.Compatibility.Satisfies(jsonschema.Compatibility(*require)) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+// diff prints the keywords which differ between the old json-schema and the
+// new one, either as text grouped by schema or as json array.
+func diff(ctx context.Context, args []string) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "jv diff [-format text|json] <old-json-schema> <new-json-schema>")
+		flags.PrintDefaults()
+	}
+	format := flags.String("format", "text", "output format: text or json")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 2 {
+		if err == nil {
+			flags.Usage()
+		}
+		return 1
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "invalid format %q\n", *format)
+		return 1
+	}
+
+	oldSchema, err := jsonschema.Compile(ctx, flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	newSchema, err := jsonschema.Compile(ctx, flags.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	diffs := jsonschema.Diff(oldSchema, newSchema)
+	if *format == "json" {
+		if diffs == nil {
+			diffs = []jsonschema.SchemaDiff{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(diffs); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	var schema string
+	for _, d := range diffs {
+		if s := d.SchemaURL + d.SchemaPtr; s != schema {
+			schema = s
+			fmt.Println(schema)
+		}
+		fmt.Printf("  %s\n", d.Change())
+	}
+	return 0
+}
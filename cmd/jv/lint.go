@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+// lint prints the smells of the given json-schemas. The exit code is 1, if
+// any finding has severity error.
+func lint(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "jv lint <json-schema>...")
+		return 1
+	}
+	code := 0
+	for _, url := range args {
+		findings, err := jsonschema.NewCompiler().Lint(ctx, url)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+			continue
+		}
+		for _, f := range findings {
+			fmt.Println(f)
+			if f.Severity == jsonschema.SeverityError {
+				code = 1
+			}
+		}
+	}
+	return code
+}
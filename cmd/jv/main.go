@@ -13,30 +13,58 @@ import (
 	_ "github.com/ory/jsonschema/v3/httploader"
 )
 
+// commands maps the name of each subcommand to its implementation. The
+// implementation returns the exit code.
+var commands = map[string]func(ctx context.Context, args []string) int{
+	"bundle":    bundle,
+	"compat":    compat,
+	"diff":      diff,
+	"lint":      lint,
+	"test":      test,
+	"testsuite": runTestsuite,
+}
+
+const usage = `jv <json-schema> [<json-doc>]...
+jv bundle <json-schema>
+jv compat [-require backward|forward|full|breaking] <old-json-schema> <new-json-schema>
+jv diff [-format text|json] <old-json-schema> <new-json-schema>
+jv lint <json-schema>...
+jv test <dir>...
+jv testsuite [-draft 4|6|7] [-remotes <dir>] <dir>...`
+
 func main() {
 	if len(os.Args) == 1 {
-		fmt.Fprintln(os.Stderr, "jv <json-schema> [<json-doc>]...")
+		fmt.Fprintln(os.Stderr, usage)
 		os.Exit(1)
 	}
 
-	schema, err := jsonschema.Compile(context.Background(), os.Args[1])
+	ctx := context.Background()
+	if cmd, ok := commands[os.Args[1]]; ok {
+		os.Exit(cmd(ctx, os.Args[2:]))
+	}
+	os.Exit(validate(ctx, os.Args[1:]))
+}
+
+func validate(ctx context.Context, args []string) int {
+	schema, err := jsonschema.Compile(ctx, args[0])
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		return 1
 	}
 
-	for _, f := range os.Args[2:] {
-		r, err := jsonschema.LoadURL(context.Background(), f)
+	for _, f := range args[1:] {
+		r, err := jsonschema.LoadURL(ctx, f)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "error in reading %q. reason: \n%v\n", f, err)
-			os.Exit(1)
+			return 1
 		}
 
 		err = schema.Validate(r)
 		_ = r.Close()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "%q does not conform to the schema specified. reason:\n%v\n", f, err)
-			os.Exit(1)
+			return 1
 		}
 	}
+	return 0
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ory/jsonschema/v3/testsuite"
+)
+
+// test runs the schema fixtures found in the given directories and prints a
+// report per schema. The exit code is 1, if a valid fixture is rejected or
+// an invalid one is accepted.
+func test(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "jv test <dir>...")
+		return 1
+	}
+	code := 0
+	for _, dir := range args {
+		report, err := new(testsuite.Runner).RunFixtures(ctx, dir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+			continue
+		}
+		if len(report.Files) == 0 {
+			fmt.Fprintf(os.Stderr, "no fixtures found in %s\n", dir)
+			code = 1
+			continue
+		}
+		if _, err := report.WriteTo(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if report.Failed() {
+			code = 1
+		}
+	}
+	return code
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/testsuite"
+)
+
+// drafts maps the values of -draft flags to drafts.
+var drafts = map[string]*jsonschema.Draft{
+	"4": jsonschema.Draft4,
+	"6": jsonschema.Draft6,
+	"7": jsonschema.Draft7,
+}
+
+// runTestsuite runs the test-suite files in the given directories and prints
+// a report per file. The exit code is 1, if any test failed.
+func runTestsuite(ctx context.Context, args []string) int {
+	flags := flag.NewFlagSet("testsuite", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "jv testsuite [-draft 4|6|7] [-remotes <dir>] <dir>...")
+		flags.PrintDefaults()
+	}
+	draft := flags.String("draft", "7", "draft used for schemas without $schema")
+	remotes := flags.String("remotes", "", "directory served at "+testsuite.RemotesURL)
+	if err := flags.Parse(args); err != nil || flags.NArg() == 0 {
+		if err == nil {
+			flags.Usage()
+		}
+		return 1
+	}
+	d, ok := drafts[*draft]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "invalid draft %q\n", *draft)
+		return 1
+	}
+
+	r := &testsuite.Runner{
+		NewCompiler: func() *jsonschema.Compiler {
+			c := jsonschema.NewCompiler()
+			c.Draft = d
+			return c
+		},
+		Remotes: *remotes,
+	}
+	code := 0
+	for _, dir := range flags.Args() {
+		report, err := r.Run(ctx, dir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+			continue
+		}
+		if _, err := report.WriteTo(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if report.Failed() {
+			code = 1
+		}
+	}
+	return code
+}
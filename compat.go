@@ -0,0 +1,694 @@
+package jsonschema
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Compatibility classifies the change from an old json-schema to a new one.
+type Compatibility string
+
+const (
+	// CompatFull is used when no change in the set of accepted instances
+	// was detected.
+	CompatFull Compatibility = "full"
+
+	// CompatBackward is used when the new schema accepts every instance
+	// which the old schema accepts, such as when an enum value is added.
+	// Documents written against the old schema remain valid.
+	CompatBackward Compatibility = "backward"
+
+	// CompatForward is used when the old schema accepts every instance
+	// which the new schema accepts, such as when maxLength is lowered.
+	// Documents written against the new schema are valid for old consumers.
+	CompatForward Compatibility = "forward"
+
+	// CompatBreaking is used when neither schema accepts every instance of
+	// the other, or when a property becomes required, which existing
+	// documents may lack.
+	CompatBreaking Compatibility = "breaking"
+)
+
+// Satisfies tells whether a change with compatibility c is allowed when
+// required compatibility is expected. CompatFull satisfies every requirement,
+// and every compatibility satisfies CompatBreaking.
+func (c Compatibility) Satisfies(required Compatibility) bool {
+	return c == CompatFull || c == required || required == CompatBreaking
+}
+
+// CompatChange describes a single change found by CheckCompatibility.
+type CompatChange struct {
+	// SchemaURL is the url of the new json-schema, in which the change
+	// was found.
+	SchemaURL string
+
+	// SchemaPtr is json-pointer which refers to the schema in the new
+	// json-schema, in which the change was found.
+	SchemaPtr string
+
+	// Compatibility is CompatBackward for changes which widen the set of
+	// accepted instances, CompatForward for changes which narrow it and
+	// CompatBreaking for changes which do both, cannot be classified or make
+	// a property required.
+	Compatibility Compatibility
+
+	// Message describes the change.
+	Message string
+}
+
+func (c CompatChange) String() string {
+	return fmt.Sprintf("%s: %s%s: %s", c.Compatibility, c.SchemaURL, c.SchemaPtr, c.Message)
+}
+
+// CompatReport is the result of CheckCompatibility.
+type CompatReport struct {
+	// Compatibility is the overall classification of the changes.
+	Compatibility Compatibility
+
+	// Changes lists the changes sorted by SchemaURL and SchemaPtr.
+	Changes []CompatChange
+}
+
+// CheckCompatibility compares the compiled schemas oldSchema and newSchema
+// structurally, keyword by keyword, and classifies the change.
+//
+// The comparison is conservative rather than exact: changes which cannot be
+// classified, such as a changed pattern or oneOf, are reported as breaking.
+// Subschemas of allOf, anyOf and oneOf are matched regardless of their order.
+// Extension keywords are not compared.
+func CheckCompatibility(oldSchema, newSchema *Schema) *CompatReport {
+	c := &compatChecker{visited: make(map[[2]*Schema]bool)}
+	c.compare(oldSchema, newSchema, newSchema.URL, newSchema.Ptr)
+
+	sort.SliceStable(c.changes, func(i, j int) bool {
+		if c.changes[i].SchemaURL != c.changes[j].SchemaURL {
+			return c.changes[i].SchemaURL < c.changes[j].SchemaURL
+		}
+		return c.changes[i].SchemaPtr < c.changes[j].SchemaPtr
+	})
+	report := &CompatReport{Compatibility: CompatFull, Changes: c.changes}
+	for _, change := range c.changes {
+		switch {
+		case report.Compatibility == CompatFull:
+			report.Compatibility = change.Compatibility
+		case report.Compatibility != change.Compatibility:
+			report.Compatibility = CompatBreaking
+		}
+	}
+	return report
+}
+
+type compatChecker struct {
+	visited map[[2]*Schema]bool
+	changes []CompatChange
+
+	// breaking is set while comparing subschemas, whose changes cannot be
+	// classified as widening or narrowing, such as the ones in oneOf.
+	breaking bool
+
+	// negated is set while comparing subschemas of not, where widening the
+	// subschema narrows the schema and vice versa.
+	negated bool
+}
+
+func (c *compatChecker) report(url, ptr string, compat Compatibility, format string, a ...interface{}) {
+	switch {
+	case c.breaking:
+		compat = CompatBreaking
+	case c.negated && compat == CompatBackward:
+		compat = CompatForward
+	case c.negated && compat == CompatForward:
+		compat = CompatBackward
+	}
+	c.changes = append(c.changes, CompatChange{
+		SchemaURL:     url,
+		SchemaPtr:     ptr,
+		Compatibility: compat,
+		Message:       fmt.Sprintf(format, a...),
+	})
+}
+
+// anySchema is used in place of missing subschemas, which accept everything.
+var anySchema = &Schema{
+	MinProperties: -1, MaxProperties: -1,
+	MinItems: -1, MaxItems: -1,
+	MinLength: -1, MaxLength: -1,
+}
+
+// noSchema is used in place of subschemas which are false.
+var noSchema = &Schema{Always: new(bool)}
+
+// subschema converts the value of keywords like additionalProperties, which
+// can be nil, bool or *Schema, to *Schema.
+func subschema(v interface{}) *Schema {
+	switch v := v.(type) {
+	case *Schema:
+		if v != nil {
+			return v
+		}
+	case bool:
+		if !v {
+			return noSchema
+		}
+	}
+	return anySchema
+}
+
+func (c *compatChecker) compare(o, n *Schema, url, ptr string) {
+	if o == nil {
+		o = anySchema
+	}
+	if n == nil {
+		n = anySchema
+	}
+	for o.Ref != nil {
+		o = o.Ref
+	}
+	for n.Ref != nil {
+		n = n.Ref
+		url, ptr = n.URL, n.Ptr
+	}
+	if c.visited[[2]*Schema{o, n}] {
+		return
+	}
+	c.visited[[2]*Schema{o, n}] = true
+
+	oAlways, nAlways := o.Always, n.Always
+	switch {
+	case oAlways != nil && !*oAlways && (nAlways == nil || *nAlways):
+		c.report(url, ptr, CompatBackward, "schema no longer rejects everything")
+		return
+	case nAlways != nil && !*nAlways && (oAlways == nil || *oAlways):
+		c.report(url, ptr, CompatForward, "schema rejects everything")
+		return
+	case oAlways != nil && nAlways != nil && *oAlways == *nAlways:
+		return
+	}
+	if oAlways != nil {
+		o = anySchema
+	}
+	if nAlways != nil {
+		n = anySchema
+	}
+
+	c.compareTypes(o, n, url, ptr)
+	c.compareEnum(o, n, url, ptr)
+	c.compareString("format", o.Format, n.Format, url, ptr)
+	c.compareString("contentEncoding", o.ContentEncoding, n.ContentEncoding, url, ptr)
+	c.compareString("contentMediaType", o.ContentMediaType, n.ContentMediaType, url, ptr)
+
+	// object validations
+	c.compareMin("minProperties", o.MinProperties, n.MinProperties, url, ptr)
+	c.compareMax("maxProperties", o.MaxProperties, n.MaxProperties, url, ptr)
+	c.compareRequired(o, n, url, ptr)
+	c.compareProperties(o, n, url, ptr)
+	if o.PropertyNames != nil || n.PropertyNames != nil {
+		c.compare(o.PropertyNames, n.PropertyNames, url, ptr+"/propertyNames")
+	}
+	c.compareDependencies(o, n, url, ptr)
+
+	// array validations
+	c.compareMin("minItems", o.MinItems, n.MinItems, url, ptr)
+	c.compareMax("maxItems", o.MaxItems, n.MaxItems, url, ptr)
+	switch {
+	case !o.UniqueItems && n.UniqueItems:
+		c.report(url, ptr, CompatForward, "items must be unique")
+	case o.UniqueItems && !n.UniqueItems:
+		c.report(url, ptr, CompatBackward, "items need not be unique")
+	}
+	c.compareItems(o, n, url, ptr)
+	switch {
+	case o.Contains == nil && n.Contains != nil:
+		c.report(url, ptr, CompatForward, "contains added")
+	case o.Contains != nil && n.Contains == nil:
+		c.report(url, ptr, CompatBackward, "contains removed")
+	case o.Contains != nil:
+		c.compare(o.Contains, n.Contains, url, ptr+"/contains")
+	}
+
+	// string validations
+	c.compareMin("minLength", o.MinLength, n.MinLength, url, ptr)
+	c.compareMax("maxLength", o.MaxLength, n.MaxLength, url, ptr)
+	c.compareString("pattern", patternString(o), patternString(n), url, ptr)
+
+	// number validations
+	c.compareMinimum("minimum", o.Minimum, n.Minimum, url, ptr)
+	c.compareMinimum("exclusiveMinimum", o.ExclusiveMinimum, n.ExclusiveMinimum, url, ptr)
+	c.compareMaximum("maximum", o.Maximum, n.Maximum, url, ptr)
+	c.compareMaximum("exclusiveMaximum", o.ExclusiveMaximum, n.ExclusiveMaximum, url, ptr)
+	c.compareMultipleOf(o.MultipleOf, n.MultipleOf, url, ptr)
+
+	c.compareSchemas(o, n, url, ptr)
+}
+
+func (c *compatChecker) compareTypes(o, n *Schema, url, ptr string) {
+	accepts := func(types []string, t string) bool {
+		if len(types) == 0 {
+			return true
+		}
+		for _, typ := range types {
+			if typ == t || (t == "integer" && typ == "number") {
+				return true
+			}
+		}
+		return false
+	}
+	for _, t := range o.Types {
+		if !accepts(n.Types, t) {
+			c.report(url, ptr, CompatForward, "type %s is no longer allowed", t)
+		}
+	}
+	if len(o.Types) > 0 && len(n.Types) == 0 {
+		c.report(url, ptr, CompatBackward, "type is no longer restricted")
+	}
+	for _, t := range n.Types {
+		if !accepts(o.Types, t) {
+			c.report(url, ptr, CompatBackward, "type %s is allowed", t)
+		}
+	}
+	if len(o.Types) == 0 && len(n.Types) > 0 {
+		c.report(url, ptr, CompatForward, "type is restricted to %s", strings.Join(n.Types, ", "))
+	}
+}
+
+func (c *compatChecker) compareEnum(o, n *Schema, url, ptr string) {
+	values := func(s *Schema) []interface{} {
+		if len(s.Constant) > 0 {
+			return s.Constant[:1]
+		}
+		return s.Enum
+	}
+	contains := func(values []interface{}, v interface{}) bool {
+		for _, value := range values {
+			if equals(value, v) {
+				return true
+			}
+		}
+		return false
+	}
+	ov, nv := values(o), values(n)
+	switch {
+	case ov == nil && nv == nil:
+	case ov == nil:
+		c.report(url, ptr, CompatForward, "values are restricted to %s", jsonValues(nv))
+	case nv == nil:
+		c.report(url, ptr, CompatBackward, "values are no longer restricted")
+	default:
+		var removed, added []interface{}
+		for _, v := range ov {
+			if !contains(nv, v) {
+				removed = append(removed, v)
+			}
+		}
+		for _, v := range nv {
+			if !contains(ov, v) {
+				added = append(added, v)
+			}
+		}
+		if len(removed) > 0 {
+			c.report(url, ptr, CompatForward, "values %s are no longer allowed", jsonValues(removed))
+		}
+		if len(added) > 0 {
+			c.report(url, ptr, CompatBackward, "values %s are allowed", jsonValues(added))
+		}
+	}
+}
+
+func jsonValues(values []interface{}) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = fmt.Sprintf("%#v", v)
+		if str, ok := v.(string); ok {
+			s[i] = strconv.Quote(str)
+		}
+	}
+	return strings.Join(s, ", ")
+}
+
+func patternString(s *Schema) string {
+	if s.Pattern == nil {
+		return ""
+	}
+	return s.Pattern.String()
+}
+
+// compareString compares the keywords whose values are strings, and which
+// restrict the instance when present.
+func (c *compatChecker) compareString(keyword, o, n, url, ptr string) {
+	switch {
+	case o == n:
+	case o == "":
+		c.report(url, ptr, CompatForward, "%s %q added", keyword, n)
+	case n == "":
+		c.report(url, ptr, CompatBackward, "%s %q removed", keyword, o)
+	default:
+		c.report(url, ptr, CompatBreaking, "%s changed from %q to %q", keyword, o, n)
+	}
+}
+
+// compareMin compares the lower bounds, which are -1 if not specified.
+func (c *compatChecker) compareMin(keyword string, o, n int, url, ptr string) {
+	switch {
+	case o == n:
+	case o == -1:
+		c.report(url, ptr, CompatForward, "%s %d added", keyword, n)
+	case n == -1:
+		c.report(url, ptr, CompatBackward, "%s %d removed", keyword, o)
+	case n > o:
+		c.report(url, ptr, CompatForward, "%s raised from %d to %d", keyword, o, n)
+	default:
+		c.report(url, ptr, CompatBackward, "%s lowered from %d to %d", keyword, o, n)
+	}
+}
+
+// compareMax compares the upper bounds, which are -1 if not specified.
+func (c *compatChecker) compareMax(keyword string, o, n int, url, ptr string) {
+	switch {
+	case o == n:
+	case o == -1:
+		c.report(url, ptr, CompatForward, "%s %d added", keyword, n)
+	case n == -1:
+		c.report(url, ptr, CompatBackward, "%s %d removed", keyword, o)
+	case n < o:
+		c.report(url, ptr, CompatForward, "%s lowered from %d to %d", keyword, o, n)
+	default:
+		c.report(url, ptr, CompatBackward, "%s raised from %d to %d", keyword, o, n)
+	}
+}
+
+func (c *compatChecker) compareMinimum(keyword string, o, n *big.Float, url, ptr string) {
+	switch {
+	case o == nil && n == nil:
+	case o == nil:
+		c.report(url, ptr, CompatForward, "%s %v added", keyword, n)
+	case n == nil:
+		c.report(url, ptr, CompatBackward, "%s %v removed", keyword, o)
+	case n.Cmp(o) > 0:
+		c.report(url, ptr, CompatForward, "%s raised from %v to %v", keyword, o, n)
+	case n.Cmp(o) < 0:
+		c.report(url, ptr, CompatBackward, "%s lowered from %v to %v", keyword, o, n)
+	}
+}
+
+func (c *compatChecker) compareMaximum(keyword string, o, n *big.Float, url, ptr string) {
+	switch {
+	case o == nil && n == nil:
+	case o == nil:
+		c.report(url, ptr, CompatForward, "%s %v added", keyword, n)
+	case n == nil:
+		c.report(url, ptr, CompatBackward, "%s %v removed", keyword, o)
+	case n.Cmp(o) < 0:
+		c.report(url, ptr, CompatForward, "%s lowered from %v to %v", keyword, o, n)
+	case n.Cmp(o) > 0:
+		c.report(url, ptr, CompatBackward, "%s raised from %v to %v", keyword, o, n)
+	}
+}
+
+func (c *compatChecker) compareMultipleOf(o, n *big.Float, url, ptr string) {
+	isMultiple := func(x, y *big.Float) bool {
+		q := new(big.Float).Quo(x, y)
+		return q.IsInt()
+	}
+	switch {
+	case o == nil && n == nil:
+	case o == nil:
+		c.report(url, ptr, CompatForward, "multipleOf %v added", n)
+	case n == nil:
+		c.report(url, ptr, CompatBackward, "multipleOf %v removed", o)
+	case o.Cmp(n) == 0:
+	case isMultiple(n, o):
+		c.report(url, ptr, CompatForward, "multipleOf changed from %v to %v", o, n)
+	case isMultiple(o, n):
+		c.report(url, ptr, CompatBackward, "multipleOf changed from %v to %v", o, n)
+	default:
+		c.report(url, ptr, CompatBreaking, "multipleOf changed from %v to %v", o, n)
+	}
+}
+
+func (c *compatChecker) compareRequired(o, n *Schema, url, ptr string) {
+	for _, pname := range n.Required {
+		if !containsString(o.Required, pname) {
+			c.report(url, ptr, CompatBreaking, "property %q is required", pname)
+		}
+	}
+	for _, pname := range o.Required {
+		if !containsString(n.Required, pname) {
+			c.report(url, ptr, CompatBackward, "property %q is no longer required", pname)
+		}
+	}
+}
+
+func containsString(arr []string, s string) bool {
+	for _, v := range arr {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// propertySchema returns the schema applied to property pname of s, and
+// whether it is known exactly. Properties matching patternProperties are not
+// known exactly.
+func propertySchema(s *Schema, pname string) (*Schema, bool) {
+	if sch, ok := s.Properties[pname]; ok {
+		return sch, true
+	}
+	for re := range s.PatternProperties {
+		if re.MatchString(pname) {
+			return nil, false
+		}
+	}
+	return subschema(s.AdditionalProperties), true
+}
+
+func (c *compatChecker) compareProperties(o, n *Schema, url, ptr string) {
+	pnames := make(map[string]bool)
+	for pname := range o.Properties {
+		pnames[pname] = true
+	}
+	for pname := range n.Properties {
+		pnames[pname] = true
+	}
+	for _, pname := range sortedKeys(pnames) {
+		osch, oExact := propertySchema(o, pname)
+		nsch, nExact := propertySchema(n, pname)
+		switch {
+		case !oExact || !nExact:
+			c.report(url, ptr, CompatBreaking, "property %q changed from or to patternProperties", pname)
+		case nsch == noSchema && osch != noSchema:
+			c.report(url, ptr, CompatForward, "property %q is no longer allowed", pname)
+		case osch == noSchema && nsch != noSchema:
+			c.report(url, ptr, CompatBackward, "property %q is allowed", pname)
+		default:
+			c.compare(osch, nsch, url, ptr+"/properties/"+escape(pname))
+		}
+	}
+
+	oPatterns, nPatterns := make(map[string]*Schema), make(map[string]*Schema)
+	for re, sch := range o.PatternProperties {
+		oPatterns[re.String()] = sch
+	}
+	for re, sch := range n.PatternProperties {
+		nPatterns[re.String()] = sch
+	}
+	for _, pattern := range sortedKeys(oPatterns) {
+		if _, ok := nPatterns[pattern]; !ok {
+			c.report(url, ptr, CompatBreaking, "patternProperties %q removed", pattern)
+		}
+	}
+	for _, pattern := range sortedKeys(nPatterns) {
+		if osch, ok := oPatterns[pattern]; ok {
+			c.compare(osch, nPatterns[pattern], url, ptr+"/patternProperties/"+escape(pattern))
+		} else {
+			c.report(url, ptr, CompatBreaking, "patternProperties %q added", pattern)
+		}
+	}
+
+	oAdditional, nAdditional := subschema(o.AdditionalProperties), subschema(n.AdditionalProperties)
+	switch {
+	case oAdditional == noSchema && nAdditional != noSchema:
+		c.report(url, ptr, CompatBackward, "additional properties are allowed")
+	case nAdditional == noSchema && oAdditional != noSchema:
+		c.report(url, ptr, CompatForward, "additional properties are no longer allowed")
+	default:
+		c.compare(oAdditional, nAdditional, url, ptr+"/additionalProperties")
+	}
+}
+
+func (c *compatChecker) compareDependencies(o, n *Schema, url, ptr string) {
+	pnames := make(map[string]bool)
+	for pname := range o.Dependencies {
+		pnames[pname] = true
+	}
+	for pname := range n.Dependencies {
+		pnames[pname] = true
+	}
+	for _, pname := range sortedKeys(pnames) {
+		od, oOK := o.Dependencies[pname]
+		nd, nOK := n.Dependencies[pname]
+		switch {
+		case !oOK:
+			c.report(url, ptr, CompatForward, "dependency of property %q added", pname)
+		case !nOK:
+			c.report(url, ptr, CompatBackward, "dependency of property %q removed", pname)
+		default:
+			switch od := od.(type) {
+			case []string:
+				nd, ok := nd.([]string)
+				if !ok {
+					c.report(url, ptr, CompatBreaking, "dependency of property %q changed", pname)
+					break
+				}
+				for _, dep := range nd {
+					if !containsString(od, dep) {
+						c.report(url, ptr, CompatForward, "property %q requires property %q", pname, dep)
+					}
+				}
+				for _, dep := range od {
+					if !containsString(nd, dep) {
+						c.report(url, ptr, CompatBackward, "property %q no longer requires property %q", pname, dep)
+					}
+				}
+			case *Schema:
+				nd, ok := nd.(*Schema)
+				if !ok {
+					c.report(url, ptr, CompatBreaking, "dependency of property %q changed", pname)
+					break
+				}
+				c.compare(od, nd, url, ptr+"/dependencies/"+escape(pname))
+			}
+		}
+	}
+}
+
+func (c *compatChecker) compareItems(o, n *Schema, url, ptr string) {
+	switch oItems := o.Items.(type) {
+	case []*Schema:
+		nItems, ok := n.Items.([]*Schema)
+		if !ok {
+			break
+		}
+		for i := 0; i < len(oItems) || i < len(nItems); i++ {
+			osch, nsch := subschema(o.AdditionalItems), subschema(n.AdditionalItems)
+			if i < len(oItems) {
+				osch = oItems[i]
+			}
+			if i < len(nItems) {
+				nsch = nItems[i]
+			}
+			c.compare(osch, nsch, url, ptr+"/items/"+strconv.Itoa(i))
+		}
+		c.compare(subschema(o.AdditionalItems), subschema(n.AdditionalItems), url, ptr+"/additionalItems")
+		return
+	default:
+		if _, ok := n.Items.([]*Schema); !ok {
+			c.compare(subschema(o.Items), subschema(n.Items), url, ptr+"/items")
+			return
+		}
+	}
+	c.report(url, ptr, CompatBreaking, "items changed between schema and array of schemas")
+}
+
+func (c *compatChecker) compareSchemas(o, n *Schema, url, ptr string) {
+	// allOf: added subschemas narrow, removed subschemas widen.
+	c.compareSubschemas("allOf", o.AllOf, n.AllOf, CompatForward, CompatBackward, url, ptr)
+
+	// anyOf: added subschemas widen, removed subschemas narrow.
+	c.compareSubschemas("anyOf", o.AnyOf, n.AnyOf, CompatBackward, CompatForward, url, ptr)
+
+	// oneOf and if cannot be classified, since changing a subschema can
+	// widen and narrow at the same time.
+	breaking := c.breaking
+	c.breaking = true
+	c.compareSubschemas("oneOf", o.OneOf, n.OneOf, CompatBreaking, CompatBreaking, url, ptr)
+	switch {
+	case o.If == nil && n.If == nil:
+	case o.If == nil:
+		c.report(url, ptr, CompatBreaking, "if added")
+	case n.If == nil:
+		c.report(url, ptr, CompatBreaking, "if removed")
+	default:
+		c.compare(o.If, n.If, url, ptr+"/if")
+	}
+	c.breaking = breaking
+	if o.If != nil && n.If != nil {
+		c.compare(o.Then, n.Then, url, ptr+"/then")
+		c.compare(o.Else, n.Else, url, ptr+"/else")
+	}
+
+	// not: narrowing the negated schema widens and vice versa.
+	switch {
+	case o.Not == nil && n.Not == nil:
+	case o.Not == nil:
+		c.report(url, ptr, CompatForward, "not added")
+	case n.Not == nil:
+		c.report(url, ptr, CompatBackward, "not removed")
+	default:
+		c.negated = !c.negated
+		c.compare(o.Not, n.Not, url, ptr+"/not")
+		c.negated = !c.negated
+	}
+}
+
+// compareSubschemas compares the subschemas of keyword, such as allOf, which
+// do not depend on their order. added and removed classify the subschemas
+// found only in n or o.
+func (c *compatChecker) compareSubschemas(keyword string, o, n []*Schema, added, removed Compatibility, url, ptr string) {
+	for _, m := range matchSchemas(o, n) {
+		switch {
+		case m[0] == -1:
+			c.report(url, ptr, added, "%s/%d added", keyword, m[1])
+		case m[1] == -1:
+			c.report(url, ptr, removed, "%s/%d removed", keyword, m[0])
+		default:
+			c.compare(o[m[0]], n[m[1]], url, ptr+"/"+keyword+"/"+strconv.Itoa(m[1]))
+		}
+	}
+}
+
+// matchSchemas pairs the indexes of the subschemas o and n. Subschemas without
+// changes are paired regardless of their order, and the remaining ones in
+// order. Subschemas left over are paired with -1.
+func matchSchemas(o, n []*Schema) [][2]int {
+	unchanged := func(o, n *Schema) bool {
+		c := &compatChecker{visited: make(map[[2]*Schema]bool)}
+		c.compare(o, n, "", "")
+		return len(c.changes) == 0
+	}
+	oMatched, nMatched := make([]bool, len(o)), make([]bool, len(n))
+	var pairs [][2]int
+	for j := range n {
+		for i := range o {
+			if !oMatched[i] && unchanged(o[i], n[j]) {
+				oMatched[i], nMatched[j] = true, true
+				pairs = append(pairs, [2]int{i, j})
+				break
+			}
+		}
+	}
+	i := 0
+	for j := range n {
+		if nMatched[j] {
+			continue
+		}
+		for i < len(o) && oMatched[i] {
+			i++
+		}
+		if i < len(o) {
+			oMatched[i] = true
+			pairs = append(pairs, [2]int{i, j})
+		} else {
+			pairs = append(pairs, [2]int{-1, j})
+		}
+	}
+	for i := range o {
+		if !oMatched[i] {
+			pairs = append(pairs, [2]int{i, -1})
+		}
+	}
+	return pairs
+}
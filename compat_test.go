@@ -0,0 +1,148 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		compat   jsonschema.Compatibility
+		changes  []string
+	}{
+		{
+			name:   "unchanged",
+			old:    `{"type": "object", "properties": {"name": {"type": "string"}}}`,
+			new:    `{"type": "object", "properties": {"name": {"type": "string"}}, "description": "user"}`,
+			compat: jsonschema.CompatFull,
+		},
+		{
+			name:    "new required property",
+			old:     `{"properties": {"name": {"type": "string"}}}`,
+			new:     `{"properties": {"name": {"type": "string"}}, "required": ["name"]}`,
+			compat:  jsonschema.CompatBreaking,
+			changes: []string{`breaking: new.json#: property "name" is required`},
+		},
+		{
+			name:    "narrowed enum",
+			old:     `{"enum": ["a", "b", "c"]}`,
+			new:     `{"enum": ["a", "b"]}`,
+			compat:  jsonschema.CompatForward,
+			changes: []string{`forward: new.json#: values "c" are no longer allowed`},
+		},
+		{
+			name:    "widened enum",
+			old:     `{"enum": ["a", "b"]}`,
+			new:     `{"enum": ["a", "b", "c"]}`,
+			compat:  jsonschema.CompatBackward,
+			changes: []string{`backward: new.json#: values "c" are allowed`},
+		},
+		{
+			name:    "lowered maxLength",
+			old:     `{"properties": {"name": {"maxLength": 100}}}`,
+			new:     `{"properties": {"name": {"maxLength": 50}}}`,
+			compat:  jsonschema.CompatForward,
+			changes: []string{`forward: new.json#/properties/name: maxLength lowered from 100 to 50`},
+		},
+		{
+			name:    "removed property under additionalProperties false",
+			old:     `{"properties": {"name": {}, "age": {}}, "additionalProperties": false}`,
+			new:     `{"properties": {"name": {}}, "additionalProperties": false}`,
+			compat:  jsonschema.CompatForward,
+			changes: []string{`forward: new.json#: property "age" is no longer allowed`},
+		},
+		{
+			name:    "integer to number",
+			old:     `{"type": "integer"}`,
+			new:     `{"type": "number"}`,
+			compat:  jsonschema.CompatBackward,
+			changes: []string{`backward: new.json#: type number is allowed`},
+		},
+		{
+			name:   "breaking",
+			old:    `{"properties": {"age": {"minimum": 0}, "tags": {"items": {"type": "string"}}}}`,
+			new:    `{"properties": {"age": {"minimum": 18}, "tags": {"items": {"type": ["string", "null"]}}}}`,
+			compat: jsonschema.CompatBreaking,
+			changes: []string{
+				`forward: new.json#/properties/age: minimum raised from 0 to 18`,
+				`backward: new.json#/properties/tags/items: type null is allowed`,
+			},
+		},
+		{
+			name:    "not",
+			old:     `{"not": {"enum": ["a"]}}`,
+			new:     `{"not": {"enum": ["a", "b"]}}`,
+			compat:  jsonschema.CompatForward,
+			changes: []string{`forward: new.json#/not: values "b" are allowed`},
+		},
+		{
+			name:    "oneOf",
+			old:     `{"oneOf": [{"type": "string"}, {"maxLength": 5}]}`,
+			new:     `{"oneOf": [{"type": "string"}, {"maxLength": 3}]}`,
+			compat:  jsonschema.CompatBreaking,
+			changes: []string{`breaking: new.json#/oneOf/1: maxLength lowered from 5 to 3`},
+		},
+		{
+			name:   "reordered allOf and oneOf",
+			old:    `{"allOf": [{"type": "object"}, {"required": ["id"]}], "oneOf": [{"type": "string"}, {"type": "integer"}]}`,
+			new:    `{"allOf": [{"required": ["id"]}, {"type": "object"}], "oneOf": [{"type": "integer"}, {"type": "string"}]}`,
+			compat: jsonschema.CompatFull,
+		},
+		{
+			name:    "reordered anyOf with changed subschema",
+			old:     `{"anyOf": [{"type": "string", "maxLength": 5}, {"type": "integer"}]}`,
+			new:     `{"anyOf": [{"type": "integer"}, {"type": "string", "maxLength": 10}, {"type": "null"}]}`,
+			compat:  jsonschema.CompatBackward,
+			changes: []string{`backward: new.json#: anyOf/2 added`, `backward: new.json#/anyOf/1: maxLength raised from 5 to 10`},
+		},
+		{
+			name:    "recursive ref",
+			old:     `{"properties": {"child": {"$ref": "#"}, "name": {"type": "string"}}}`,
+			new:     `{"definitions": {"node": {"properties": {"child": {"$ref": "#/definitions/node"}, "name": {"type": "string", "minLength": 1}}}}, "$ref": "#/definitions/node"}`,
+			compat:  jsonschema.CompatForward,
+			changes: []string{`forward: new.json#/definitions/node/properties/name: minLength 1 added`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			old, err := jsonschema.CompileString(ctx, "old.json", test.old)
+			if err != nil {
+				t.Fatal(err)
+			}
+			new, err := jsonschema.CompileString(ctx, "new.json", test.new)
+			if err != nil {
+				t.Fatal(err)
+			}
+			report := jsonschema.CheckCompatibility(old, new)
+			var changes []string
+			for _, change := range report.Changes {
+				changes = append(changes, change.String())
+			}
+			if report.Compatibility != test.compat {
+				t.Errorf("compatibility: got %s, want %s", report.Compatibility, test.compat)
+			}
+			if strings.Join(changes, "\n") != strings.Join(test.changes, "\n") {
+				t.Errorf("changes:\ngot:\n%s\nwant:\n%s", strings.Join(changes, "\n"), strings.Join(test.changes, "\n"))
+			}
+		})
+	}
+}
+
+func TestCompatibility_Satisfies(t *testing.T) {
+	if !jsonschema.CompatFull.Satisfies(jsonschema.CompatBackward) {
+		t.Error("full must satisfy backward")
+	}
+	if jsonschema.CompatForward.Satisfies(jsonschema.CompatBackward) {
+		t.Error("forward must not satisfy backward")
+	}
+	if jsonschema.CompatBackward.Satisfies(jsonschema.CompatFull) {
+		t.Error("backward must not satisfy full")
+	}
+	if !jsonschema.CompatBreaking.Satisfies(jsonschema.CompatBreaking) {
+		t.Error("breaking must satisfy breaking")
+	}
+}
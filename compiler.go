@@ -46,6 +46,27 @@ type Compiler struct {
 	//
 	// If nil, package global LoadURL is used.
 	LoadURL func(ctx context.Context, s string) (io.ReadCloser, error)
+
+	// Strict tells whether compilation fails on keywords, which are neither
+	// compiled nor claimed by a registered extension. A keyword is claimed by
+	// an extension, if it is one of the properties of the extension's Meta.
+	//
+	// The returned error is *SchemaError with *UnknownKeywordsError.
+	Strict bool
+
+	// ErrorMessages tells whether the errorMessage keyword, compatible with
+	// ajv-errors, is compiled. It replaces the messages of validation errors,
+	// see ErrorMessage.
+	ErrorMessages bool
+
+	// Redaction, if not nil, masks sensitive values in the validation errors
+	// of the compiled schemas.
+	Redaction *Redaction
+
+	// AllowedKeywords lists the unknown keywords accepted in strict mode.
+	// An entry ending with "*" allows all keywords with that prefix, such as
+	// "x-*" for vendor extensions.
+	AllowedKeywords []string
 }
 
 // NewCompiler returns a json-schema Compiler object.
@@ -94,6 +115,22 @@ func (c *Compiler) MustCompile(ctx context.Context, url string) *Schema {
 // a Schema object that can be used to match against json.
 func (c *Compiler) Compile(ctx context.Context, url string) (*Schema, error) {
 	base, fragment := split(url)
+	r, err := c.resource(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	if c.Strict && !r.strict {
+		if err := c.checkKeywords(r); err != nil {
+			return nil, err
+		}
+		r.strict = true
+	}
+	return c.compileRef(ctx, r, r.url, fragment)
+}
+
+// resource returns the resource at given url, loading it if it was not added
+// to the compiler yet. url must not have fragment.
+func (c *Compiler) resource(ctx context.Context, base string) (*resource, error) {
 	if _, ok := c.resources[base]; !ok {
 		r, err := c.loadURL(ctx, base)
 		if err != nil {
@@ -111,11 +148,11 @@ func (c *Compiler) Compile(ctx context.Context, url string) (*Schema, error) {
 				switch url {
 				case "http://json-schema.org/schema#":
 					r.draft = latest
-				case "http://json-schema.org/draft-07/schema#":
+				case "http://json-schema.org/draft-07/schema#", "http://json-schema.org/draft-07/hyper-schema#":
 					r.draft = Draft7
-				case "http://json-schema.org/draft-06/schema#":
+				case "http://json-schema.org/draft-06/schema#", "http://json-schema.org/draft-06/hyper-schema#":
 					r.draft = Draft6
-				case "http://json-schema.org/draft-04/schema#":
+				case "http://json-schema.org/draft-04/schema#", "http://json-schema.org/draft-04/hyper-schema#":
 					r.draft = Draft4
 				default:
 					return nil, fmt.Errorf("unknown $schema %q", url)
@@ -126,7 +163,7 @@ func (c *Compiler) Compile(ctx context.Context, url string) (*Schema, error) {
 			r.draft = c.Draft
 		}
 	}
-	return c.compileRef(ctx, r, r.url, fragment)
+	return r, nil
 }
 
 func (c Compiler) loadURL(ctx context.Context, s string) (io.ReadCloser, error) {
@@ -224,6 +261,13 @@ func (c *Compiler) compileMap(ctx context.Context, r *resource, s *Schema, base
 		}
 	}
 
+	s.readOnly = m["readOnly"] == true
+	s.writeOnly = m["writeOnly"] == true
+	if c.Redaction != nil {
+		s.redaction = c.Redaction
+		s.sensitive = c.Redaction.sensitive(m)
+	}
+
 	if ref, ok := m["$ref"]; ok {
 		b, _ := split(base)
 		s.Ref, err = c.compileRef(ctx, r, b, ref.(string))
@@ -245,25 +289,6 @@ func (c *Compiler) compileMap(ctx context.Context, r *resource, s *Schema, base
 
 	if e, ok := m["enum"]; ok {
 		s.Enum = e.([]interface{})
-		allPrimitives := true
-		for _, item := range s.Enum {
-			switch jsonType(item) {
-			case "object", "array":
-				allPrimitives = false
-			}
-		}
-		s.enumError = "enum failed"
-		if allPrimitives {
-			if len(s.Enum) == 1 {
-				s.enumError = fmt.Sprintf("value must be %#v", s.Enum[0])
-			} else {
-				strEnum := make([]string, len(s.Enum))
-				for i, item := range s.Enum {
-					strEnum[i] = fmt.Sprintf("%#v", item)
-				}
-				s.enumError = fmt.Sprintf("value must be one of %s", strings.Join(strEnum, ", "))
-			}
-		}
 	}
 
 	loadSchema := func(pname string) (*Schema, error) {
@@ -448,6 +473,14 @@ func (c *Compiler) compileMap(ctx context.Context, r *resource, s *Schema, base
 
 	s.MultipleOf = loadFloat("multipleOf")
 
+	if c.ErrorMessages {
+		if msg, ok := m["errorMessage"]; ok {
+			if s.ErrorMessage, err = compileErrorMessage(msg); err != nil {
+				return err
+			}
+		}
+	}
+
 	if c.ExtractAnnotations {
 		if title, ok := m["title"]; ok {
 			s.Title = title.(string)
@@ -526,10 +559,11 @@ func (c *Compiler) validateSchema(r *resource, ptr string, v interface{}) error
 		if meta == nil {
 			return nil
 		}
-		if err := meta.validate(v); err != nil {
+		if err := meta.validate(v, nil); err != nil {
 			_ = addContext(ptr, "", err)
 			finishSchemaContext(err, meta)
 			finishInstanceContext(err)
+			localize(err.(*ValidationError), nil)
 			var instancePtr string
 			if ptr == "" {
 				instancePtr = "#"
@@ -561,6 +595,23 @@ func (c *Compiler) validateSchema(r *resource, ptr string, v interface{}) error
 	return nil
 }
 
+// checkKeywords returns *SchemaError, if any schema of resource r has unknown
+// keywords.
+func (c *Compiler) checkKeywords(r *resource) error {
+	unknown := make(map[string][]string)
+	known := c.knownKeywords(r.draft)
+	_ = walkSchemas(r.draft, r.url, "", r.doc, func(_, ptr string, m map[string]interface{}) error {
+		if keywords := c.unknownKeywords(known, m); len(keywords) > 0 {
+			unknown["#"+ptr] = keywords
+		}
+		return nil
+	})
+	if len(unknown) > 0 {
+		return &SchemaError{r.url, &UnknownKeywordsError{Keywords: unknown}}
+	}
+	return nil
+}
+
 func toStrings(arr []interface{}) []string {
 	s := make([]string, len(arr))
 	for i, v := range arr {
@@ -0,0 +1,163 @@
+package jsonschema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/ory/jsonschema/v3/internal/jsonvalue"
+)
+
+// Dereference returns a json document equivalent to the compiled schema s, in
+// which every "$ref" is replaced by the schema it refers to.
+//
+// A recursive "$ref" cannot be replaced. It is left as a json-pointer to the
+// enclosing schema it refers to, in the returned document. Since no other
+// "$ref" is left, "definitions" and ids are removed from the document.
+//
+// s must have been compiled by c, and must be the result of Compile, not one of
+// its subschemas.
+func (c *Compiler) Dereference(ctx context.Context, s *Schema) (interface{}, error) {
+	if s.Ptr == "" {
+		return nil, errors.New("jsonschema: Dereference called with subschema")
+	}
+	l := newLocator(c)
+	url := normalize(s.URL + s.Ptr)
+	var r *resource
+	var ptr string
+	if res, ok := c.resources[s.URL]; ok {
+		var err error
+		if r, ptr, err = l.locate(ctx, res, url); err != nil {
+			return nil, err
+		}
+	} else {
+		// s was compiled from a schema with id, within another resource.
+		urls := make([]string, 0, len(c.resources))
+		for u := range c.resources {
+			urls = append(urls, u)
+		}
+		sort.Strings(urls)
+		for _, u := range urls {
+			res := c.resources[u]
+			if res.draft == nil {
+				continue
+			}
+			if p, ok := l.index(res)[url]; ok {
+				r, ptr = res, p
+				break
+			}
+		}
+		if r == nil {
+			return nil, fmt.Errorf("jsonschema: schema %q was not compiled by this compiler", url)
+		}
+	}
+	d := &dereferencer{locator: l, draft: r.draft, stack: make(map[string]string)}
+	base, doc, err := r.resolve(ptr)
+	if err != nil {
+		return nil, err
+	}
+	return d.deref(ctx, r, base, ptr, "", doc)
+}
+
+type dereferencer struct {
+	*locator
+	draft *Draft
+	stack map[string]string // json-pointer in result, by url of the schemas being dereferenced.
+}
+
+// deref returns a dereferenced copy of schema v, which is located at json-pointer
+// ptr of resource r. outPtr is the json-pointer to the copy in the result.
+func (d *dereferencer) deref(ctx context.Context, r *resource, base, ptr, outPtr string, v interface{}) (interface{}, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v, nil
+	}
+	if r.draft != d.draft {
+		return nil, fmt.Errorf("cannot dereference %q: drafts differ", r.url+"#"+ptr)
+	}
+	if id, ok := m[r.draft.id].(string); ok {
+		var err error
+		if base, err = resolveURL(base, id); err != nil {
+			return nil, err
+		}
+	}
+
+	key := r.url + "#" + ptr
+	d.stack[key] = outPtr
+	defer delete(d.stack, key)
+
+	if ref, ok := m["$ref"].(string); ok {
+		target, targetPtr, err := d.resolveRef(ctx, r, base, ref)
+		if err != nil {
+			return nil, err
+		}
+		if p, ok := d.stack[target.url+"#"+targetPtr]; ok {
+			return map[string]interface{}{"$ref": "#" + p}, nil
+		}
+		targetBase, doc, err := target.resolve(targetPtr)
+		if err != nil {
+			return nil, err
+		}
+		// All other properties in a "$ref" object MUST be ignored
+		return d.deref(ctx, target, targetBase, targetPtr, outPtr, doc)
+	}
+
+	keywords := make(map[string]bool)
+	for _, pname := range schemaKeywords(r.draft) {
+		keywords[pname] = true
+	}
+	result := make(map[string]interface{}, len(m))
+	for pname, pvalue := range m {
+		ptr, outPtr := ptr+"/"+escape(pname), outPtr+"/"+escape(pname)
+		switch {
+		case pname == r.draft.id || pname == "definitions":
+			continue
+		case !keywords[pname]:
+			result[pname] = jsonvalue.Copy(pvalue)
+			continue
+		}
+		switch pvalue := pvalue.(type) {
+		case []interface{}:
+			arr := make([]interface{}, len(pvalue))
+			for i, item := range pvalue {
+				token := "/" + strconv.Itoa(i)
+				var err error
+				if arr[i], err = d.deref(ctx, r, base, ptr+token, outPtr+token, item); err != nil {
+					return nil, err
+				}
+			}
+			result[pname] = arr
+		case map[string]interface{}:
+			switch pname {
+			case "properties", "patternProperties", "dependencies":
+				props := make(map[string]interface{}, len(pvalue))
+				for name, item := range pvalue {
+					token := "/" + escape(name)
+					var err error
+					if props[name], err = d.deref(ctx, r, base, ptr+token, outPtr+token, item); err != nil {
+						return nil, err
+					}
+				}
+				result[pname] = props
+			default:
+				var err error
+				if result[pname], err = d.deref(ctx, r, base, ptr, outPtr, pvalue); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			result[pname] = pvalue
+		}
+	}
+	return result, nil
+}
+
+// resolve returns the base url and the value at json-pointer ptr.
+func (r *resource) resolve(ptr string) (string, interface{}, error) {
+	if ptr == "" {
+		return r.url, r.doc, nil
+	}
+	return r.resolvePtr("#" + ptr)
+}
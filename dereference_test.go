@@ -0,0 +1,125 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+func TestDereference(t *testing.T) {
+	for _, tc := range []struct {
+		description string
+		resources   map[string]string
+		url         string
+		expected    string
+	}{
+		{
+			description: "local and external refs",
+			resources: map[string]string{
+				"http://example.com/user.json": `{
+					"$id": "http://example.com/user.json",
+					"properties": {
+						"name": {"$ref": "name.json", "description": "ignored"},
+						"email": {"$ref": "#/definitions/email"}
+					},
+					"definitions": {"email": {"type": "string"}}
+				}`,
+				"http://example.com/name.json": `{"type": "string", "maxLength": 5}`,
+			},
+			url:      "http://example.com/user.json",
+			expected: `{"properties": {"name": {"type": "string", "maxLength": 5}, "email": {"type": "string"}}}`,
+		},
+		{
+			description: "recursive refs",
+			resources: map[string]string{
+				"tree.json": `{
+					"type": "object",
+					"properties": {
+						"children": {"type": "array", "items": {"$ref": "#"}},
+						"meta": {"$ref": "#/definitions/meta"}
+					},
+					"definitions": {
+						"meta": {"properties": {"parent": {"$ref": "#/definitions/meta"}}}
+					}
+				}`,
+			},
+			url: "tree.json",
+			expected: `{
+				"type": "object",
+				"properties": {
+					"children": {"type": "array", "items": {"$ref": "#"}},
+					"meta": {"properties": {"parent": {"$ref": "#/properties/meta"}}}
+				}
+			}`,
+		},
+		{
+			description: "id scoping",
+			resources: map[string]string{
+				"http://example.com/root.json": `{
+					"items": {
+						"$id": "http://example.com/nested/",
+						"items": [{"$ref": "item.json"}, {"$ref": "#/definitions/local"}]
+					},
+					"definitions": {
+						"anchored": {"$id": "#anchor", "type": "integer"},
+						"local": {"const": 1}
+					},
+					"not": {"$ref": "#anchor"}
+				}`,
+				"http://example.com/nested/item.json": `{"type": "boolean"}`,
+			},
+			url:      "http://example.com/root.json",
+			expected: `{"items": {"items": [{"type": "boolean"}, {"const": 1}]}, "not": {"type": "integer"}}`,
+		},
+		{
+			description: "fragment",
+			resources: map[string]string{
+				"defs.json": `{"definitions": {"a": {"$ref": "#/definitions/b"}, "b": {"type": "null"}}}`,
+			},
+			url:      "defs.json#/definitions/a",
+			expected: `{"type": "null"}`,
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			for url, doc := range tc.resources {
+				if err := c.AddResource(url, strings.NewReader(doc)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			s, err := c.Compile(ctx, tc.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc, err := c.Dereference(ctx, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, err := jsonschema.DecodeJSON(strings.NewReader(tc.expected))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ := json.Marshal(doc)
+			want, _ := json.Marshal(expected)
+			if string(got) != string(want) {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+
+	t.Run("subschema", func(t *testing.T) {
+		c := jsonschema.NewCompiler()
+		if err := c.AddResource("a.json", strings.NewReader(`{"not": {}}`)); err != nil {
+			t.Fatal(err)
+		}
+		s, err := c.Compile(ctx, "a.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.Dereference(ctx, s.Not); err == nil {
+			t.Error("error expected")
+		}
+	})
+}
@@ -0,0 +1,346 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DiffOp tells how a keyword differs between two schemas.
+type DiffOp string
+
+const (
+	// DiffAdded is used for keywords present only in the new schema.
+	DiffAdded DiffOp = "added"
+
+	// DiffRemoved is used for keywords present only in the old schema.
+	DiffRemoved DiffOp = "removed"
+
+	// DiffChanged is used for keywords whose values differ.
+	DiffChanged DiffOp = "changed"
+)
+
+// SchemaDiff describes a keyword which differs between two schemas compared
+// by Diff.
+type SchemaDiff struct {
+	// SchemaURL is the url of the new json-schema, in which the keyword
+	// differs.
+	SchemaURL string `json:"schemaURL"`
+
+	// SchemaPtr is json-pointer which refers to the schema in the new
+	// json-schema, in which the keyword differs.
+	SchemaPtr string `json:"schemaPtr"`
+
+	// Keyword is the keyword relative to SchemaPtr, such as "maxLength" or
+	// "properties/name" for a subschema.
+	Keyword string `json:"keyword"`
+
+	Op DiffOp `json:"op"`
+
+	// Old and New are the values of the keyword, as json values. They are nil
+	// for subschemas, unless the subschema is boolean.
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+}
+
+func (d SchemaDiff) String() string {
+	op, change := d.change()
+	return fmt.Sprintf("%s %s%s: %s", op, d.SchemaURL, d.SchemaPtr, change)
+}
+
+// Change returns the keyword and its values, without the location of the
+// schema, such as "+ maxLength 5", "- minimum 1" or "~ maxLength 5 -> 10".
+// Values of subschemas are omitted, unless they are boolean.
+func (d SchemaDiff) Change() string {
+	op, change := d.change()
+	return op + " " + change
+}
+
+func (d SchemaDiff) change() (op, change string) {
+	switch d.Op {
+	case DiffAdded:
+		return "+", d.Keyword + diffValue(d.New)
+	case DiffRemoved:
+		return "-", d.Keyword + diffValue(d.Old)
+	default:
+		// only boolean subschemas change, other subschemas are compared
+		// keyword by keyword.
+		oldValue, newValue := diffValue(d.Old), diffValue(d.New)
+		if oldValue == "" {
+			oldValue = " {...}"
+		}
+		if newValue == "" {
+			newValue = " {...}"
+		}
+		return "~", d.Keyword + oldValue + " ->" + newValue
+	}
+}
+
+func diffValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf(" %v", v)
+	}
+	return " " + string(b)
+}
+
+// Diff compares the compiled schemas oldSchema and newSchema, and returns the
+// keywords which were added, removed or changed, sorted by SchemaURL,
+// SchemaPtr and Keyword.
+//
+// $refs are followed, so that moving a schema into definitions is not
+// reported as a difference. Values are compared semantically: the order of
+// type, enum and required is ignored, and numbers are compared by value.
+// Annotations are compared only if they were extracted, and extension
+// keywords are not compared.
+func Diff(oldSchema, newSchema *Schema) []SchemaDiff {
+	d := &differ{visited: make(map[[2]*Schema]bool)}
+	d.diff(oldSchema, newSchema, newSchema.URL, newSchema.Ptr, newSchema.Ptr, "")
+	sort.SliceStable(d.diffs, func(i, j int) bool {
+		di, dj := d.diffs[i], d.diffs[j]
+		if di.SchemaURL != dj.SchemaURL {
+			return di.SchemaURL < dj.SchemaURL
+		}
+		if di.SchemaPtr != dj.SchemaPtr {
+			return di.SchemaPtr < dj.SchemaPtr
+		}
+		return di.Keyword < dj.Keyword
+	})
+	return d.diffs
+}
+
+type differ struct {
+	visited map[[2]*Schema]bool
+	diffs   []SchemaDiff
+}
+
+func (d *differ) add(url, ptr, keyword string, op DiffOp, oldValue, newValue interface{}) {
+	d.diffs = append(d.diffs, SchemaDiff{url, ptr, keyword, op, oldValue, newValue})
+}
+
+// diff compares schemas o and n, which are the values of keyword in the
+// schema at parentPtr.
+func (d *differ) diff(o, n *Schema, url, parentPtr, ptr, keyword string) {
+	for o.Ref != nil {
+		o = o.Ref
+	}
+	for n.Ref != nil {
+		n = n.Ref
+		url, ptr = n.URL, n.Ptr
+	}
+	if d.visited[[2]*Schema{o, n}] {
+		return
+	}
+	d.visited[[2]*Schema{o, n}] = true
+
+	if o.Always != nil && !*o.Always || n.Always != nil && !*n.Always {
+		if boolSchema(o) != boolSchema(n) {
+			d.add(url, parentPtr, keyword, DiffChanged, boolSchema(o), boolSchema(n))
+		}
+		return
+	}
+
+	oValues, oChildren := flatten(o)
+	nValues, nChildren := flatten(n)
+	for _, k := range sortedKeys(oValues) {
+		nv, ok := nValues[k]
+		switch {
+		case !ok:
+			d.add(url, ptr, k, DiffRemoved, oValues[k], nil)
+		case !sameValue(k, oValues[k], nv):
+			d.add(url, ptr, k, DiffChanged, oValues[k], nv)
+		}
+	}
+	for _, k := range sortedKeys(nValues) {
+		if _, ok := oValues[k]; !ok {
+			d.add(url, ptr, k, DiffAdded, nil, nValues[k])
+		}
+	}
+	for _, k := range sortedKeys(oChildren) {
+		if nc, ok := nChildren[k]; ok {
+			d.diff(oChildren[k], nc, url, ptr, ptr+"/"+k, k)
+		} else {
+			d.add(url, ptr, k, DiffRemoved, boolSchema(oChildren[k]), nil)
+		}
+	}
+	for _, k := range sortedKeys(nChildren) {
+		if _, ok := oChildren[k]; !ok {
+			d.add(url, ptr, k, DiffAdded, nil, boolSchema(nChildren[k]))
+		}
+	}
+}
+
+// boolSchema returns the value of boolean schema s, or nil if s is not
+// boolean.
+func boolSchema(s *Schema) interface{} {
+	if s.Always != nil {
+		return *s.Always
+	}
+	return nil
+}
+
+// flatten returns the keywords of s, split into json values and subschemas.
+// The keys of subschemas are relative json-pointers, such as "allOf/1".
+func flatten(s *Schema) (map[string]interface{}, map[string]*Schema) {
+	values, children := make(map[string]interface{}), make(map[string]*Schema)
+	if s.Always != nil {
+		// true schema has no keywords.
+		return values, children
+	}
+	str := func(k, v string) {
+		if v != "" {
+			values[k] = v
+		}
+	}
+	integer := func(k string, v int) {
+		if v != -1 {
+			values[k] = json.Number(strconv.Itoa(v))
+		}
+	}
+	number := func(k string, v *big.Float) {
+		if v != nil {
+			values[k] = json.Number(v.Text('g', -1))
+		}
+	}
+	list := func(k string, v []string) {
+		if len(v) > 0 {
+			values[k] = stringsValue(v)
+		}
+	}
+	child := func(k string, v interface{}) {
+		switch v := v.(type) {
+		case *Schema:
+			if v != nil {
+				children[k] = v
+			}
+		case bool:
+			if !v {
+				children[k] = noSchema
+			}
+		}
+	}
+	childArray := func(k string, v []*Schema) {
+		for i, sch := range v {
+			children[k+"/"+strconv.Itoa(i)] = sch
+		}
+	}
+
+	list("type", s.Types)
+	if len(s.Constant) > 0 {
+		values["const"] = s.Constant[0]
+	}
+	if s.Enum != nil {
+		values["enum"] = s.Enum
+	}
+	str("format", s.Format)
+	child("not", s.Not)
+	childArray("allOf", s.AllOf)
+	childArray("anyOf", s.AnyOf)
+	childArray("oneOf", s.OneOf)
+	child("if", s.If)
+	child("then", s.Then)
+	child("else", s.Else)
+
+	integer("minProperties", s.MinProperties)
+	integer("maxProperties", s.MaxProperties)
+	list("required", s.Required)
+	for pname, sch := range s.Properties {
+		children["properties/"+escape(pname)] = sch
+	}
+	child("propertyNames", s.PropertyNames)
+	for re, sch := range s.PatternProperties {
+		children["patternProperties/"+escape(re.String())] = sch
+	}
+	child("additionalProperties", s.AdditionalProperties)
+	for pname, dep := range s.Dependencies {
+		if dep, ok := dep.([]string); ok {
+			values["dependencies/"+escape(pname)] = stringsValue(dep)
+			continue
+		}
+		child("dependencies/"+escape(pname), dep)
+	}
+
+	integer("minItems", s.MinItems)
+	integer("maxItems", s.MaxItems)
+	if s.UniqueItems {
+		values["uniqueItems"] = true
+	}
+	switch items := s.Items.(type) {
+	case []*Schema:
+		childArray("items", items)
+	default:
+		child("items", items)
+	}
+	child("additionalItems", s.AdditionalItems)
+	child("contains", s.Contains)
+
+	integer("minLength", s.MinLength)
+	integer("maxLength", s.MaxLength)
+	if s.Pattern != nil {
+		values["pattern"] = s.Pattern.String()
+	}
+	str("contentEncoding", s.ContentEncoding)
+	str("contentMediaType", s.ContentMediaType)
+
+	number("minimum", s.Minimum)
+	number("exclusiveMinimum", s.ExclusiveMinimum)
+	number("maximum", s.Maximum)
+	number("exclusiveMaximum", s.ExclusiveMaximum)
+	number("multipleOf", s.MultipleOf)
+
+	str("title", s.Title)
+	str("description", s.Description)
+	if s.Default != nil {
+		values["default"] = s.Default
+	}
+	if s.ReadOnly {
+		values["readOnly"] = true
+	}
+	if s.WriteOnly {
+		values["writeOnly"] = true
+	}
+	if s.Examples != nil {
+		values["examples"] = s.Examples
+	}
+	return values, children
+}
+
+func stringsValue(v []string) []interface{} {
+	arr := make([]interface{}, len(v))
+	for i, s := range v {
+		arr[i] = s
+	}
+	return arr
+}
+
+// sameValue tells whether the values of keyword k are equal. The order of
+// items is ignored for keywords whose value is a set.
+func sameValue(k string, v1, v2 interface{}) bool {
+	if k != "type" && k != "enum" && k != "required" && !strings.HasPrefix(k, "dependencies/") {
+		return equals(v1, v2)
+	}
+	arr1, ok1 := v1.([]interface{})
+	arr2, ok2 := v2.([]interface{})
+	if !ok1 || !ok2 || len(arr1) != len(arr2) {
+		return ok1 == ok2 && equals(v1, v2)
+	}
+	for _, item1 := range arr1 {
+		found := false
+		for _, item2 := range arr2 {
+			if equals(item1, item2) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		diffs    []string
+	}{
+		{
+			name: "unchanged",
+			old:  `{"type": ["string", "null"], "enum": ["a", "b", null], "minimum": 1.0}`,
+			new:  `{"type": ["null", "string"], "enum": [null, "b", "a"], "minimum": 1}`,
+		},
+		{
+			name: "keywords",
+			old:  `{"properties": {"name": {"maxLength": 100, "pattern": "^a"}, "age": {}}, "required": ["name"]}`,
+			new:  `{"properties": {"name": {"maxLength": 50, "format": "email"}, "id": {}}, "required": ["name", "id"]}`,
+			diffs: []string{
+				`- new.json#: properties/age`,
+				`+ new.json#: properties/id`,
+				`~ new.json#: required ["name"] -> ["name","id"]`,
+				`+ new.json#/properties/name: format "email"`,
+				`~ new.json#/properties/name: maxLength 100 -> 50`,
+				`- new.json#/properties/name: pattern "^a"`,
+			},
+		},
+		{
+			name: "moved into definitions",
+			old:  `{"properties": {"address": {"properties": {"zip": {"type": "string"}}}}}`,
+			new:  `{"properties": {"address": {"$ref": "#/definitions/address"}}, "definitions": {"address": {"properties": {"zip": {"type": "string"}}}}}`,
+		},
+		{
+			name: "changed definition",
+			old:  `{"properties": {"address": {"$ref": "#/definitions/address"}}, "definitions": {"address": {"properties": {"zip": {"type": "string"}}}}}`,
+			new:  `{"properties": {"address": {"$ref": "#/definitions/address"}}, "definitions": {"address": {"properties": {"zip": {"type": "integer"}}}}}`,
+			diffs: []string{
+				`~ new.json#/definitions/address/properties/zip: type ["string"] -> ["integer"]`,
+			},
+		},
+		{
+			name: "boolean schemas",
+			old:  `{"additionalProperties": false, "items": [{}, {}]}`,
+			new:  `{"additionalProperties": {"type": "string"}, "items": [{}]}`,
+			diffs: []string{
+				`~ new.json#: additionalProperties false -> {...}`,
+				`- new.json#: items/1`,
+			},
+		},
+		{
+			name: "recursive",
+			old:  `{"properties": {"child": {"$ref": "#"}}}`,
+			new:  `{"properties": {"child": {"$ref": "#"}}, "minProperties": 1}`,
+			diffs: []string{
+				`+ new.json#: minProperties 1`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			old, err := jsonschema.CompileString(ctx, "old.json", test.old)
+			if err != nil {
+				t.Fatal(err)
+			}
+			new, err := jsonschema.CompileString(ctx, "new.json", test.new)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var diffs []string
+			for _, d := range jsonschema.Diff(old, new) {
+				diffs = append(diffs, d.String())
+				if want := strings.Replace(d.String(), " "+d.SchemaURL+d.SchemaPtr+":", "", 1); d.Change() != want {
+					t.Errorf("got change %q, want %q", d.Change(), want)
+				}
+			}
+			if strings.Join(diffs, "\n") != strings.Join(test.diffs, "\n") {
+				t.Errorf("got:\n%s\nwant:\n%s", strings.Join(diffs, "\n"), strings.Join(test.diffs, "\n"))
+			}
+		})
+	}
+}
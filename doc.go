@@ -42,6 +42,8 @@ To load json-schema from HTTPURL, add following import:
 Loading from urls for other schemes (such as ftp), can be plugged in. see package jsonschema/httploader
 for an example
 
+To load json-schema from an fs.FS, such as an embed.FS, see package jsonschema/fsloader.
+
 To load json-schema from in-memory:
 
 	data := `{"type": "string"}`
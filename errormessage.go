@@ -0,0 +1,151 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrorMessage holds the messages of the errorMessage keyword, which is
+// compatible with ajv-errors. It is compiled only when
+// Compiler.ErrorMessages is true.
+//
+// The keyword is either a string, which replaces the messages of all errors
+// of the schema, or an object:
+//
+//	{
+//		"errorMessage": {
+//			"pattern": "Use only letters",
+//			"properties": {"email": "Enter a valid email address"},
+//			"items": ["First item must be a name"],
+//			"_": "Invalid value"
+//		}
+//	}
+//
+// The messages of keywords are strings. Objects mapping property names to
+// messages, which ajv-errors allows for keywords like "required" and
+// "dependencies", are not supported, since "required" reports all missing
+// properties in one error, and fail to compile.
+//
+// Errors keep their pointers, keyword and context; only Message is replaced.
+// Messages of a subschema's errorMessage take precedence.
+type ErrorMessage struct {
+	// Message replaces the messages of all errors not covered by the other
+	// fields. It is the string value of the keyword, or its "_" property.
+	Message string
+
+	// Keywords replaces the messages of the errors caused by a keyword,
+	// including errors of its subschemas.
+	Keywords map[string]string
+
+	// Properties replaces the messages of the errors of a property.
+	Properties map[string]string
+
+	// Items replaces the messages of the errors of the item at the index.
+	Items []string
+}
+
+func compileErrorMessage(v interface{}) (*ErrorMessage, error) {
+	m := &ErrorMessage{}
+	switch v := v.(type) {
+	case string:
+		m.Message = v
+		return m, nil
+	case map[string]interface{}:
+		for k, kv := range v {
+			var ok bool
+			switch k {
+			case "_":
+				m.Message, ok = kv.(string)
+			case "properties":
+				m.Properties, ok = toStringMap(kv)
+			case "items":
+				var items []interface{}
+				if items, ok = kv.([]interface{}); ok {
+					m.Items = make([]string, len(items))
+					for i, item := range items {
+						if m.Items[i], ok = item.(string); !ok {
+							break
+						}
+					}
+				}
+			default:
+				if _, obj := kv.(map[string]interface{}); obj {
+					return nil, fmt.Errorf("invalid errorMessage: object of %q is not supported", k)
+				}
+				if m.Keywords == nil {
+					m.Keywords = make(map[string]string)
+				}
+				m.Keywords[k], ok = kv.(string)
+			}
+			if !ok {
+				return nil, fmt.Errorf("invalid errorMessage: %q must be string", k)
+			}
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("invalid errorMessage: must be string or object")
+}
+
+func toStringMap(v interface{}) (map[string]string, bool) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	m := make(map[string]string, len(obj))
+	for k, v := range obj {
+		if m[k], ok = v.(string); !ok {
+			return nil, false
+		}
+	}
+	return m, true
+}
+
+// apply replaces the messages of err, which is returned by the schema.
+func (m *ErrorMessage) apply(err error) {
+	ve := err.(*ValidationError)
+	causes := []*ValidationError{ve}
+	if ve.SchemaPtr == "" && len(ve.Causes) > 0 {
+		// errors of the schema, grouped
+		causes = ve.Causes
+	}
+	for _, cause := range causes {
+		if msg, ok := m.message(cause.SchemaPtr); ok {
+			cause.setCustomMessage(msg)
+		}
+	}
+	if m.Message != "" {
+		ve.setCustomMessage(m.Message)
+	}
+}
+
+// message returns the message for the errors at schemaPtr, relative to the
+// schema.
+func (m *ErrorMessage) message(schemaPtr string) (string, bool) {
+	tokens := strings.SplitN(schemaPtr, "/", 3)
+	if len(tokens) > 1 {
+		switch tokens[0] {
+		case "properties":
+			if msg, ok := m.Properties[unescape(tokens[1])]; ok {
+				return msg, true
+			}
+		case "items":
+			if i, err := strconv.Atoi(tokens[1]); err == nil && i < len(m.Items) {
+				return m.Items[i], true
+			}
+		}
+	}
+	msg, ok := m.Keywords[tokens[0]]
+	return msg, ok
+}
+
+// setCustomMessage replaces the messages of the error tree rooted at ve,
+// which are not replaced already.
+func (ve *ValidationError) setCustomMessage(msg string) {
+	if !ve.customMessage {
+		ve.Message, ve.customMessage = msg, true
+	}
+	for _, cause := range ve.Causes {
+		cause.setCustomMessage(msg)
+	}
+}
@@ -0,0 +1,168 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/jsonschematest"
+)
+
+const userErrorMessages = `{
+	"type": "object",
+	"required": ["name", "email"],
+	"properties": {
+		"name": {
+			"type": "string",
+			"minLength": 2,
+			"pattern": "^[a-zA-Z]+$",
+			"errorMessage": {"pattern": "Use only letters"}
+		},
+		"email": {"type": "string", "format": "email"},
+		"tags": {
+			"items": [{"type": "string"}, {"type": "integer"}],
+			"additionalItems": {"type": "string", "errorMessage": "Tags must be strings"}
+		},
+		"age": {"type": "integer", "minimum": 0, "errorMessage": "Enter your age"}
+	},
+	"errorMessage": {
+		"required": "Name and email are required",
+		"properties": {"email": "Enter a valid email address"},
+		"items": ["unused"],
+		"_": "Invalid user"
+	}
+}`
+
+func TestErrorMessage(t *testing.T) {
+	for _, tc := range []struct {
+		schema string
+		doc    string
+		opts   []jsonschema.ValidationOption
+		want   string
+	}{
+		{
+			schema: userErrorMessages,
+			doc:    `{"name": "1"}`,
+			want: `I[#] S[test.json#] Invalid user
+  I[#] S[test.json#/required] Name and email are required
+  I[#/name] S[test.json#/properties/name] Invalid user
+    I[#/name] S[test.json#/properties/name/minLength] Invalid user
+    I[#/name] S[test.json#/properties/name/pattern] Use only letters
+`,
+		},
+		{
+			schema: userErrorMessages,
+			doc:    `{"name": "Alice", "email": "alice", "age": -1, "tags": ["a", 1, 2]}`,
+			want: `I[#] S[test.json#] Invalid user
+  I[#/age] S[test.json#/properties/age/minimum] Enter your age
+  I[#/email] S[test.json#/properties/email/format] Enter a valid email address
+  I[#/tags/2] S[test.json#/properties/tags/additionalItems/type] Tags must be strings
+`,
+		},
+		{
+			schema: userErrorMessages,
+			doc:    `[]`,
+			want:   "I[#] S[test.json#/type] Invalid user\n",
+		},
+		{
+			schema: `{
+				"items": [{"type": "string"}, {"type": "string"}],
+				"errorMessage": {"items": ["First item must be a name"]}
+			}`,
+			doc: `[1, 2]`,
+			want: `I[#] S[test.json#] validation failed
+  I[#/0] S[test.json#/items/0/type] First item must be a name
+  I[#/1] S[test.json#/items/1/type] expected string, but got number
+`,
+		},
+		{
+			// messages of errorMessage are not localized
+			schema: userErrorMessages,
+			doc:    `{"name": "Alice", "email": "alice", "age": "x"}`,
+			opts: []jsonschema.ValidationOption{jsonschema.WithCatalog(jsonschema.Catalog{
+				"":       "ungültig",
+				"format": "ungültiges Format",
+			})},
+			want: `I[#] S[test.json#] Invalid user
+  I[#/age] S[test.json#/properties/age/type] Enter your age
+  I[#/email] S[test.json#/properties/email/format] Enter a valid email address
+`,
+		},
+	} {
+		c := jsonschema.NewCompiler()
+		c.ErrorMessages = true
+		c.Strict = true
+		if err := c.AddResource("test.json", strings.NewReader(tc.schema)); err != nil {
+			t.Fatal(err)
+		}
+		schema, err := c.Compile(ctx, "test.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = schema.ValidateInterface(decode(t, tc.doc), tc.opts...)
+		verr, ok := err.(*jsonschema.ValidationError)
+		if !ok {
+			t.Errorf("%s: got %v, want *ValidationError", tc.doc, err)
+			continue
+		}
+		if got := jsonschematest.Render(verr); got != tc.want {
+			t.Errorf("%s:\ngot:\n%s\nwant:\n%s", tc.doc, got, tc.want)
+		}
+	}
+}
+
+func TestErrorMessageKeepsContext(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	c.ErrorMessages = true
+	if err := c.AddResource("test.json", strings.NewReader(`{"minLength": 3, "errorMessage": {"minLength": "Too short"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := c.Compile(ctx, "test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verr := jsonschematest.AssertInvalid(t, schema, `"ab"`)
+	if verr == nil {
+		t.FailNow()
+	}
+	if verr.Message != "Too short" || verr.Keyword != "minLength" || verr.SchemaPtr != "#/minLength" {
+		t.Errorf("unexpected error %s", verr.MessageFmt())
+	}
+	if _, ok := verr.Context.(*jsonschema.ValidationErrorContextMinLength); !ok {
+		t.Errorf("expected *ValidationErrorContextMinLength, got %T", verr.Context)
+	}
+}
+
+func TestErrorMessageDisabled(t *testing.T) {
+	schema, err := jsonschema.CompileString(ctx, "test.json", `{"minLength": 3, "errorMessage": "Too short"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonschematest.AssertInvalidAt(t, schema, `"ab"`, "#", "minLength")
+	if schema.ErrorMessage != nil {
+		t.Error("errorMessage must not be compiled")
+	}
+}
+
+func TestErrorMessageInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		schema string
+		want   string
+	}{
+		{`{"errorMessage": 1}`, "invalid errorMessage: must be string or object"},
+		{`{"errorMessage": {"pattern": 1}}`, `invalid errorMessage: "pattern" must be string`},
+		{`{"errorMessage": {"properties": {"name": true}}}`, `invalid errorMessage: "properties" must be string`},
+		{`{"errorMessage": {"items": ["a", 1]}}`, `invalid errorMessage: "items" must be string`},
+		{`{"errorMessage": {"required": {"name": "Enter your name"}}}`, `invalid errorMessage: object of "required" is not supported`},
+		{`{"errorMessage": {"dependencies": {"b": "b needs a"}}}`, `invalid errorMessage: object of "dependencies" is not supported`},
+	} {
+		c := jsonschema.NewCompiler()
+		c.ErrorMessages = true
+		if err := c.AddResource("test.json", strings.NewReader(tc.schema)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.Compile(ctx, "test.json"); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: got %v, want %q", tc.schema, err, tc.want)
+		}
+	}
+}
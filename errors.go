@@ -6,6 +6,8 @@ package jsonschema
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -33,6 +35,30 @@ func (se *SchemaError) Error() string {
 	return fmt.Sprintf("json-schema %q compilation failed. Reason:\n%s", se.SchemaURL, se.Err)
 }
 
+// UnknownKeywordsError is the error wrapped by SchemaError, when the schema
+// has unknown keywords and Compiler.Strict is set.
+type UnknownKeywordsError struct {
+	// Keywords maps json-pointers of schemas to their unknown keywords.
+	Keywords map[string][]string
+}
+
+func (e *UnknownKeywordsError) Error() string {
+	ptrs := make([]string, 0, len(e.Keywords))
+	for ptr := range e.Keywords {
+		ptrs = append(ptrs, ptr)
+	}
+	sort.Strings(ptrs)
+	msg := "unknown keywords:"
+	for _, ptr := range ptrs {
+		keywords := make([]string, len(e.Keywords[ptr]))
+		for i, k := range e.Keywords[ptr] {
+			keywords[i] = strconv.Quote(k)
+		}
+		msg += fmt.Sprintf("\n  S[%s] %s", ptr, strings.Join(keywords, ", "))
+	}
+	return msg
+}
+
 // ValidationError is the error type returned by Validate.
 type ValidationError struct {
 	// Message describes error
@@ -50,11 +76,21 @@ type ValidationError struct {
 	// that failed to satisfy
 	SchemaPtr string
 
+	// Keyword is the keyword that failed, such as "minLength". It is empty,
+	// if the error only groups its causes, or if the schema is false.
+	Keyword string
+
 	// Context represents error context for this specific validation error.
+	// It holds the parameters of the failed keyword, such as
+	// *ValidationErrorContextMinLength for "minLength".
 	Context ValidationErrorContext
 
 	// Causes details the nested validation errors
 	Causes []*ValidationError
+
+	customMessage bool // Message is replaced by errorMessage keyword.
+	sensitive     bool // value is redacted, see Redaction.
+	propertyName  bool // value is the property name, InstancePtr refers to.
 }
 
 func (ve *ValidationError) add(causes ...error) error {
@@ -80,8 +116,28 @@ func (ve *ValidationError) Error() string {
 	return msg
 }
 
+func (ve *ValidationError) withContext(ctx ValidationErrorContext) *ValidationError {
+	ve.Context = ctx
+	return ve
+}
+
+// validationErrorf returns the error of the keyword, which is the first token
+// of schemaPtr.
 func validationErrorf(schemaPtr string, format string, a ...interface{}) *ValidationError {
-	return &ValidationError{fmt.Sprintf(format, a...), "", "", schemaPtr, nil, nil}
+	ve := keywordError(schemaPtr)
+	ve.Message = fmt.Sprintf(format, a...)
+	return ve
+}
+
+// keywordError returns the error of the keyword, which is the first token of
+// schemaPtr. Its message is rendered from the English catalog, with the
+// context of the error, when the error is reported.
+func keywordError(schemaPtr string) *ValidationError {
+	keyword := schemaPtr
+	if i := strings.IndexByte(keyword, '/'); i != -1 {
+		keyword = keyword[:i]
+	}
+	return &ValidationError{SchemaPtr: schemaPtr, Keyword: keyword}
 }
 
 func addContext(instancePtr, schemaPtr string, err error) error {
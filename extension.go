@@ -46,13 +46,15 @@ func (ctx CompilerContext) CompileRef(c context.Context, ref string) (*Schema, e
 }
 
 // ValidationContext provides additional context required in validating for extension.
-type ValidationContext struct{}
+type ValidationContext struct {
+	o *validationOptions
+}
 
 // Validate validates schema s with value v. Extension must use this method instead of
 // *Schema.ValidateInterface method. This will be useful in implementing keywords like
 // allOf/oneOf
-func (ValidationContext) Validate(s *Schema, v interface{}) error {
-	return s.validate(v)
+func (ctx ValidationContext) Validate(s *Schema, v interface{}) error {
+	return s.validate(v, ctx.o)
 }
 
 // Error used to construct validation error by extensions. schemaPtr is relative json pointer.
@@ -0,0 +1,77 @@
+// Package fsloader implements a loader for schemas stored in an fs.FS, such
+// as an embed.FS, with the function signature used by jsonschema.Loaders
+// and Compiler.LoadURL.
+//
+// Unlike the other loaders, fsloader is not registered as a side effect of
+// importing it. Instead, the loader returned by New is set on a Compiler:
+//
+//	//go:embed schemas
+//	var schemas embed.FS
+//
+//	c := jsonschema.NewCompiler()
+//	c.LoadURL = fsloader.New(schemas)
+//	s, err := c.Compile(ctx, "schemas/user.json")
+//
+// Relative "$ref"s between the files are resolved against the url of the
+// referring schema, so "schemas/user.json" may refer to "address.json" and
+// it is loaded from "schemas/address.json".
+package fsloader
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+// Scheme is the url scheme which may be used to address files in the fs.FS
+// explicitly, e.g. "fs:///schemas/user.json".
+const Scheme = "fs"
+
+// New returns a loader which reads schemas from fsys.
+//
+// Urls without scheme are treated as slash separated paths into fsys, and so
+// are urls with the "fs" scheme. Urls with any other scheme are passed on to
+// jsonschema.LoadURL, so that references to remote schemas keep working.
+func New(fsys fs.FS) func(ctx context.Context, url string) (io.ReadCloser, error) {
+	return func(ctx context.Context, s string) (io.ReadCloser, error) {
+		name, ok, err := toPath(s)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return jsonschema.LoadURL(ctx, s)
+		}
+		return fsys.Open(name)
+	}
+}
+
+// toPath converts url s into a path valid for fs.FS. It returns false if s
+// does not address a file in the fs.FS.
+func toPath(s string) (string, bool, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", false, err
+	}
+	var p string
+	switch u.Scheme {
+	case "":
+		// resolveURL joins relative references using package filepath.
+		p = filepath.ToSlash(s)
+	case Scheme:
+		p = u.Path
+	default:
+		return "", false, nil
+	}
+	// rooting the path before cleaning it keeps ".." from escaping fsys.
+	p = strings.TrimPrefix(path.Clean("/"+p), "/")
+	if p == "" {
+		p = "."
+	}
+	return p, true, nil
+}
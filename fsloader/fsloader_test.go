@@ -0,0 +1,66 @@
+package fsloader_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/fsloader"
+)
+
+var fsys = fstest.MapFS{
+	"schemas/user.json": {Data: []byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"$ref": "definitions/name.json"},
+			"address": {"$ref": "address.json#/definitions/address"}
+		}
+	}`)},
+	"schemas/address.json": {Data: []byte(`{
+		"definitions": {
+			"address": {"type": "object", "required": ["street"]}
+		}
+	}`)},
+	"schemas/definitions/name.json": {Data: []byte(`{
+		"allOf": [{"$ref": "../common.json"}, {"maxLength": 5}]
+	}`)},
+	"schemas/common.json": {Data: []byte(`{"type": "string"}`)},
+}
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	for _, url := range []string{
+		"schemas/user.json",
+		"./schemas/user.json",
+		"fs:///schemas/user.json",
+	} {
+		t.Run("url="+url, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			c.LoadURL = fsloader.New(fsys)
+			s, err := c.Compile(ctx, url)
+			require.NoError(t, err)
+
+			assert.NoError(t, s.Validate(strings.NewReader(`{"name": "foo", "address": {"street": "bar"}}`)))
+			assert.Error(t, s.Validate(strings.NewReader(`{"name": "foobar"}`)))
+			assert.Error(t, s.Validate(strings.NewReader(`{"name": 1}`)))
+			assert.Error(t, s.Validate(strings.NewReader(`{"address": {}}`)))
+		})
+	}
+
+	t.Run("case=missing file", func(t *testing.T) {
+		c := jsonschema.NewCompiler()
+		c.LoadURL = fsloader.New(fsys)
+		_, err := c.Compile(ctx, "schemas/missing.json")
+		require.Error(t, err)
+	})
+
+	t.Run("case=other schemes are delegated", func(t *testing.T) {
+		_, err := fsloader.New(fsys)(ctx, "unknown://schemas/user.json")
+		assert.ErrorIs(t, err, jsonschema.SchemeNotRegisteredError("unknown"))
+	})
+}
@@ -0,0 +1,628 @@
+// Package generator generates json instances, which are valid against a
+// compiled json-schema, for property-based tests and for seeding local
+// environments.
+//
+//	g := generator.New(42)
+//	doc, err := g.Generate(schema)
+//
+// The same seed generates the same instances for the same schema. Use a seed
+// like time.Now().UnixNano() to generate random instances.
+//
+// The generator merges the constraints of allOf and of the chosen anyOf, oneOf,
+// then and else subschemas, generates a candidate and validates it against the
+// schemas. Candidates which fail, for example because of not or a pattern which
+// contradicts maxLength, are generated again, until Generator.Attempts is
+// exhausted.
+//
+// Mutate and Generator.Mutations complement valid instances with invalid
+// ones, one per constraint, each labelled with the keyword expected to fail:
+//
+//	mutations, err := g.Mutations(schema)
+//	for _, m := range mutations {
+//		// assert the API rejects m.Instance, reporting m.SchemaPtr
+//	}
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"math"
+	"math/big"
+	"math/rand"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+// ErrUnsatisfiable is returned, when no valid instance was found.
+var ErrUnsatisfiable = errors.New("generator: no valid instance found")
+
+// Generator generates json instances valid against a schema. The generated
+// instances use the same types as jsonschema.DecodeJSON, with numbers
+// as json.Number.
+//
+// A Generator must not be used concurrently.
+type Generator struct {
+	// MaxDepth limits the nesting of generated instances. Beyond it, only
+	// required properties and minItems items are generated.
+	MaxDepth int
+
+	// MaxItems bounds the number of array items and object properties,
+	// where the schema does not bound them.
+	MaxItems int
+
+	// Attempts is the number of candidates generated for each schema, before
+	// ErrUnsatisfiable is returned.
+	Attempts int
+
+	rand *rand.Rand
+}
+
+// New returns a Generator, whose instances are determined by seed.
+func New(seed int64) *Generator {
+	return &Generator{
+		MaxDepth: 5,
+		MaxItems: 3,
+		Attempts: 100,
+		rand:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Generate returns an instance, which is valid against s.
+//
+// Returned error is ErrUnsatisfiable, if no valid instance was found.
+func (g *Generator) Generate(s *jsonschema.Schema) (interface{}, error) {
+	return g.generate([]*jsonschema.Schema{s}, 0)
+}
+
+// types lists all json types, in the order tried for unconstrained schemas.
+var types = []string{"string", "integer", "number", "boolean", "null", "object", "array"}
+
+// generate returns an instance, which is valid against all schemas.
+func (g *Generator) generate(schemas []*jsonschema.Schema, depth int) (interface{}, error) {
+	for i := 0; i < g.Attempts; i++ {
+		v, err := g.candidate(schemas, depth)
+		if err != nil {
+			continue
+		}
+		if valid(schemas, v) {
+			return v, nil
+		}
+	}
+	return nil, ErrUnsatisfiable
+}
+
+func valid(schemas []*jsonschema.Schema, v interface{}) bool {
+	for _, s := range schemas {
+		if s.ValidateInterface(v) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// candidate returns an instance, which satisfies the merged constraints of
+// schemas. The instance need not be valid.
+func (g *Generator) candidate(schemas []*jsonschema.Schema, depth int) (interface{}, error) {
+	c, err := g.merge(schemas)
+	if err != nil {
+		return nil, err
+	}
+	if c.values != nil {
+		return c.values[g.rand.Intn(len(c.values))], nil
+	}
+
+	candidates := c.types()
+	if len(candidates) == 0 {
+		return nil, ErrUnsatisfiable
+	}
+	switch t := candidates[g.rand.Intn(len(candidates))]; t {
+	case "null":
+		return nil, nil
+	case "boolean":
+		return g.rand.Intn(2) == 1, nil
+	case "integer", "number":
+		return g.number(c, t == "integer")
+	case "string":
+		return g.string(c)
+	case "array":
+		return g.array(c, depth)
+	default:
+		return g.object(c, depth)
+	}
+}
+
+// constraints are the merged constraints of a list of schemas. Subschemas
+// are kept as lists, which are merged when generating the nested instances.
+type constraints struct {
+	schemas []*jsonschema.Schema
+
+	typeSets [][]string
+	values   []interface{} // nil, if not restricted by const or enum.
+
+	// numbers
+	min, max                   *big.Float
+	exclusiveMin, exclusiveMax bool
+	multipleOf                 *big.Float
+
+	// strings
+	minLength, maxLength int
+	format               string
+	pattern              string
+
+	// arrays and objects use schemas directly.
+	minItems, maxItems int
+	minProps, maxProps int
+}
+
+// merge flattens the $refs and allOfs of schemas, and chooses a subschema
+// of each anyOf, oneOf and if.
+func (g *Generator) merge(schemas []*jsonschema.Schema) (*constraints, error) {
+	c := &constraints{minLength: -1, maxLength: -1, minItems: -1, maxItems: -1, minProps: -1, maxProps: -1}
+	seen := make(map[*jsonschema.Schema]bool)
+	var add func(s *jsonschema.Schema) error
+	add = func(s *jsonschema.Schema) error {
+		for s.Ref != nil {
+			s = s.Ref
+		}
+		if seen[s] {
+			return nil
+		}
+		seen[s] = true
+		if s.Always != nil {
+			if !*s.Always {
+				return ErrUnsatisfiable
+			}
+			return nil
+		}
+		c.add(s)
+		for _, sub := range s.AllOf {
+			if err := add(sub); err != nil {
+				return err
+			}
+		}
+		if len(s.AnyOf) > 0 {
+			if err := add(s.AnyOf[g.rand.Intn(len(s.AnyOf))]); err != nil {
+				return err
+			}
+		}
+		if len(s.OneOf) > 0 {
+			if err := add(s.OneOf[g.rand.Intn(len(s.OneOf))]); err != nil {
+				return err
+			}
+		}
+		if s.If != nil {
+			if g.rand.Intn(2) == 0 {
+				if err := add(s.If); err != nil {
+					return err
+				}
+				if s.Then != nil {
+					return add(s.Then)
+				}
+			} else if s.Else != nil {
+				return add(s.Else)
+			}
+		}
+		return nil
+	}
+	for _, s := range schemas {
+		if err := add(s); err != nil {
+			return nil, err
+		}
+	}
+	if c.values != nil {
+		// keep only the values valid against all schemas.
+		var values []interface{}
+		for _, v := range c.values {
+			if valid(c.schemas, v) {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return nil, ErrUnsatisfiable
+		}
+		c.values = values
+	}
+	return c, nil
+}
+
+func (c *constraints) add(s *jsonschema.Schema) {
+	c.schemas = append(c.schemas, s)
+	if len(s.Types) > 0 {
+		c.typeSets = append(c.typeSets, s.Types)
+	}
+	if len(s.Constant) > 0 {
+		c.values = s.Constant[:1]
+	} else if s.Enum != nil && c.values == nil {
+		c.values = s.Enum
+	}
+
+	if s.Minimum != nil && (c.min == nil || s.Minimum.Cmp(c.min) > 0) {
+		c.min, c.exclusiveMin = s.Minimum, false
+	}
+	if s.ExclusiveMinimum != nil && (c.min == nil || s.ExclusiveMinimum.Cmp(c.min) >= 0) {
+		c.min, c.exclusiveMin = s.ExclusiveMinimum, true
+	}
+	if s.Maximum != nil && (c.max == nil || s.Maximum.Cmp(c.max) < 0) {
+		c.max, c.exclusiveMax = s.Maximum, false
+	}
+	if s.ExclusiveMaximum != nil && (c.max == nil || s.ExclusiveMaximum.Cmp(c.max) <= 0) {
+		c.max, c.exclusiveMax = s.ExclusiveMaximum, true
+	}
+	if s.MultipleOf != nil && c.multipleOf == nil {
+		c.multipleOf = s.MultipleOf
+	}
+
+	c.minLength, c.maxLength = maxInt(c.minLength, s.MinLength), minInt(c.maxLength, s.MaxLength)
+	if s.Format != "" && c.format == "" {
+		c.format = s.Format
+	}
+	if s.Pattern != nil && c.pattern == "" {
+		c.pattern = s.Pattern.String()
+	}
+
+	c.minItems, c.maxItems = maxInt(c.minItems, s.MinItems), minInt(c.maxItems, s.MaxItems)
+	c.minProps, c.maxProps = maxInt(c.minProps, s.MinProperties), minInt(c.maxProps, s.MaxProperties)
+}
+
+// maxInt returns the larger of the bounds a and b, which are -1 if not
+// specified.
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// minInt returns the smaller of the bounds a and b, which are -1 if not
+// specified.
+func minInt(a, b int) int {
+	switch {
+	case a == -1:
+		return b
+	case b == -1 || a < b:
+		return a
+	}
+	return b
+}
+
+// types returns the types allowed by all type sets. Without type sets, the
+// types are guessed from the keywords used.
+func (c *constraints) types() []string {
+	allowed := func(t string) bool {
+		for _, set := range c.typeSets {
+			ok := false
+			for _, typ := range set {
+				if typ == t || (t == "integer" && typ == "number") {
+					ok = true
+				}
+			}
+			if !ok {
+				return false
+			}
+		}
+		return true
+	}
+	var result []string
+	for _, t := range types {
+		if allowed(t) {
+			result = append(result, t)
+		}
+	}
+	if len(c.typeSets) > 0 {
+		return result
+	}
+
+	var hinted []string
+	for _, t := range result {
+		if c.hints(t) {
+			hinted = append(hinted, t)
+		}
+	}
+	if len(hinted) > 0 {
+		return hinted
+	}
+	return result
+}
+
+// hints tells whether the schemas use keywords which apply to type t.
+func (c *constraints) hints(t string) bool {
+	switch t {
+	case "integer", "number":
+		return c.min != nil || c.max != nil || c.multipleOf != nil
+	case "string":
+		return c.minLength != -1 || c.maxLength != -1 || c.format != "" || c.pattern != ""
+	}
+	for _, s := range c.schemas {
+		switch t {
+		case "object":
+			if len(s.Required) > 0 || len(s.Properties) > 0 || len(s.PatternProperties) > 0 ||
+				s.AdditionalProperties != nil || s.MinProperties != -1 || s.MaxProperties != -1 {
+				return true
+			}
+		case "array":
+			if s.Items != nil || s.Contains != nil || s.MinItems != -1 || s.MaxItems != -1 || s.UniqueItems {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (g *Generator) number(c *constraints, integer bool) (interface{}, error) {
+	step := 1.0
+	if c.multipleOf != nil {
+		step, _ = c.multipleOf.Float64()
+		// integers must be multiples of the smallest integral multiple
+		// of step.
+		for k := 2.0; integer && step != math.Trunc(step) && k <= 100; k++ {
+			if m := step * k; m == math.Trunc(m) {
+				step = m
+			}
+		}
+	} else if !integer {
+		step = 0.25
+	}
+
+	lo, hi := -100.0, 100.0
+	if c.min != nil {
+		lo, _ = c.min.Float64()
+		if c.max == nil {
+			hi = lo + 100*step
+		}
+	}
+	if c.max != nil {
+		hi, _ = c.max.Float64()
+		if c.min == nil {
+			lo = hi - 100*step
+		}
+	}
+	kmin, kmax := math.Ceil(lo/step), math.Floor(hi/step)
+	if c.exclusiveMin && kmin*step <= lo {
+		kmin++
+	}
+	if c.exclusiveMax && kmax*step >= hi {
+		kmax--
+	}
+	if kmin > kmax {
+		if c.multipleOf != nil || integer {
+			return nil, ErrUnsatisfiable
+		}
+		// bounds are closer than step.
+		return json.Number(strconv.FormatFloat((lo+hi)/2, 'g', -1, 64)), nil
+	}
+	k := kmin + float64(g.rand.Int63n(int64(math.Min(kmax-kmin, 1e9))+1))
+	return json.Number(strconv.FormatFloat(k*step, 'g', -1, 64)), nil
+}
+
+func (g *Generator) string(c *constraints) (interface{}, error) {
+	minLength, maxLength := c.minLength, c.maxLength
+	if minLength == -1 {
+		minLength = 0
+	}
+	if maxLength == -1 {
+		maxLength = minLength + 10
+	}
+	if minLength > maxLength {
+		return nil, ErrUnsatisfiable
+	}
+	switch {
+	case c.format != "" && formats[c.format] != nil:
+		return formats[c.format](g.rand), nil
+	case c.pattern != "":
+		return g.pattern(c.pattern)
+	}
+	n := minLength + g.rand.Intn(maxLength-minLength+1)
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphanumeric[g.rand.Intn(len(alphanumeric))]
+	}
+	return string(b), nil
+}
+
+const alphanumeric = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+func (g *Generator) array(c *constraints, depth int) (interface{}, error) {
+	minItems, maxItems := c.minItems, c.maxItems
+	if minItems == -1 {
+		minItems = 0
+	}
+	if maxItems == -1 {
+		maxItems = minItems + g.MaxItems
+	}
+	if depth >= g.MaxDepth {
+		maxItems = minItems
+	}
+	if minItems > maxItems {
+		return nil, ErrUnsatisfiable
+	}
+	unique := false
+	for _, s := range c.schemas {
+		unique = unique || s.UniqueItems
+	}
+
+	n := minItems + g.rand.Intn(maxItems-minItems+1)
+	arr := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		var schemas []*jsonschema.Schema
+		for _, s := range c.schemas {
+			if sch := itemSchema(s, i); sch != nil {
+				schemas = append(schemas, sch)
+			}
+			if i == 0 && s.Contains != nil {
+				schemas = append(schemas, s.Contains)
+			}
+		}
+		item, err := g.generateUnique(schemas, depth+1, arr, unique)
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, item)
+	}
+	return arr, nil
+}
+
+// generateUnique generates an instance, which is not equal to any of arr
+// when unique is set.
+func (g *Generator) generateUnique(schemas []*jsonschema.Schema, depth int, arr []interface{}, unique bool) (interface{}, error) {
+	for i := 0; i < g.Attempts; i++ {
+		v, err := g.generate(schemas, depth)
+		if err != nil {
+			return nil, err
+		}
+		if !unique || !contains(arr, v) {
+			return v, nil
+		}
+	}
+	return nil, ErrUnsatisfiable
+}
+
+func contains(arr []interface{}, v interface{}) bool {
+	b, _ := json.Marshal(v)
+	for _, item := range arr {
+		if ib, _ := json.Marshal(item); string(ib) == string(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// itemSchema returns the schema of i-th item of arrays valid against s.
+func itemSchema(s *jsonschema.Schema, i int) *jsonschema.Schema {
+	switch items := s.Items.(type) {
+	case *jsonschema.Schema:
+		return items
+	case []*jsonschema.Schema:
+		if i < len(items) {
+			return items[i]
+		}
+		if additional, ok := s.AdditionalItems.(*jsonschema.Schema); ok {
+			return additional
+		}
+		if additional, ok := s.AdditionalItems.(bool); ok && !additional {
+			return falseSchema
+		}
+	}
+	return nil
+}
+
+var falseSchema = &jsonschema.Schema{Always: new(bool)}
+
+func (g *Generator) object(c *constraints, depth int) (interface{}, error) {
+	minProps, maxProps := c.minProps, c.maxProps
+	if minProps == -1 {
+		minProps = 0
+	}
+	if maxProps == -1 {
+		maxProps = 1 << 30
+	}
+
+	// required properties, including the ones required by dependencies of
+	// other required properties.
+	var names []string
+	required := make(map[string]bool)
+	var require func(pname string)
+	require = func(pname string) {
+		if required[pname] {
+			return
+		}
+		required[pname] = true
+		names = append(names, pname)
+		for _, s := range c.schemas {
+			if dep, ok := s.Dependencies[pname].([]string); ok {
+				for _, d := range dep {
+					require(d)
+				}
+			}
+		}
+	}
+	for _, s := range c.schemas {
+		for _, pname := range s.Required {
+			require(pname)
+		}
+	}
+
+	// optional properties, which are declared in properties.
+	var optional []string
+	for _, s := range c.schemas {
+		for _, pname := range slices.Sorted(maps.Keys(s.Properties)) {
+			if !required[pname] && !slices.Contains(optional, pname) {
+				optional = append(optional, pname)
+			}
+		}
+	}
+	g.rand.Shuffle(len(optional), func(i, j int) { optional[i], optional[j] = optional[j], optional[i] })
+	extra := 0
+	if depth < g.MaxDepth {
+		extra = g.rand.Intn(len(optional) + 1)
+	}
+	extra = min(extra, g.MaxItems, maxProps-len(names))
+	if need := minProps - len(names); need > extra {
+		extra = need
+	}
+	for _, pname := range optional {
+		if extra == 0 {
+			break
+		}
+		require(pname)
+		extra--
+	}
+	for i := 1; extra > 0; i++ {
+		pname := "property" + strconv.Itoa(i)
+		if !required[pname] {
+			require(pname)
+			extra--
+		}
+	}
+
+	obj := make(map[string]interface{}, len(names))
+	for _, pname := range names {
+		var schemas []*jsonschema.Schema
+		for _, s := range c.schemas {
+			schemas = append(schemas, propertySchemas(s, pname)...)
+		}
+		v, err := g.generate(schemas, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		obj[pname] = v
+	}
+	return obj, nil
+}
+
+// propertySchemas returns the schemas of property pname of objects valid
+// against s.
+func propertySchemas(s *jsonschema.Schema, pname string) []*jsonschema.Schema {
+	var schemas []*jsonschema.Schema
+	if sch, ok := s.Properties[pname]; ok {
+		schemas = append(schemas, sch)
+	}
+	for _, re := range sortedPatterns(s.PatternProperties) {
+		if re.MatchString(pname) {
+			schemas = append(schemas, s.PatternProperties[re])
+		}
+	}
+	if len(schemas) == 0 {
+		switch additional := s.AdditionalProperties.(type) {
+		case *jsonschema.Schema:
+			schemas = append(schemas, additional)
+		case bool:
+			if !additional {
+				schemas = append(schemas, falseSchema)
+			}
+		}
+	}
+	return schemas
+}
+
+// sortedPatterns returns the patterns of patternProperties m, sorted, so that
+// the same seed generates the same instance.
+func sortedPatterns(m map[*regexp.Regexp]*jsonschema.Schema) []*regexp.Regexp {
+	return slices.SortedFunc(maps.Keys(m), func(re1, re2 *regexp.Regexp) int {
+		return strings.Compare(re1.String(), re2.String())
+	})
+}
@@ -0,0 +1,137 @@
+package generator_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/generator"
+)
+
+var schemas = map[string]string{
+	"empty":    `{}`,
+	"string":   `{"type": "string", "minLength": 3, "maxLength": 5}`,
+	"enum":     `{"enum": ["red", "green", 3]}`,
+	"const":    `{"const": {"a": [1, 2]}}`,
+	"integer":  `{"type": "integer", "minimum": 10, "exclusiveMaximum": 20, "multipleOf": 3}`,
+	"number":   `{"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 0.1}`,
+	"fraction": `{"type": "integer", "multipleOf": 1.5}`,
+	"formats": `{
+		"type": "object",
+		"required": ["email", "created", "id", "ip", "host", "uri"],
+		"properties": {
+			"email": {"format": "email"},
+			"created": {"format": "date-time"},
+			"id": {"type": "string", "format": "uuid"},
+			"ip": {"format": "ipv6"},
+			"host": {"format": "hostname"},
+			"uri": {"format": "uri"}
+		}
+	}`,
+	"pattern": `{"type": "string", "pattern": "^[A-Z]{2}-\\d{3,5}(-[a-f]+)?$"}`,
+	"object": `{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string"},
+			"age": {"type": "integer", "minimum": 0}
+		},
+		"dependencies": {"age": ["birthday"]},
+		"additionalProperties": {"type": "boolean"},
+		"minProperties": 2
+	}`,
+	"closed":   `{"properties": {"a": {"type": "null"}}, "additionalProperties": false, "minProperties": 1}`,
+	"array":    `{"type": "array", "items": {"enum": [1, 2, 3, 4]}, "minItems": 2, "maxItems": 4, "uniqueItems": true}`,
+	"tuple":    `{"type": "array", "items": [{"type": "string"}, {"type": "boolean"}], "additionalItems": false, "minItems": 2}`,
+	"contains": `{"type": "array", "contains": {"const": "x"}, "minItems": 1}`,
+	"oneOf":    `{"oneOf": [{"type": "string", "maxLength": 2}, {"type": "string", "minLength": 1}]}`,
+	"allOf":    `{"allOf": [{"type": "string"}, {"minLength": 4}, {"maxLength": 4}]}`,
+	"not":      `{"type": "integer", "minimum": 0, "maximum": 3, "not": {"enum": [0, 1, 2]}}`,
+	"if": `{
+		"type": "object",
+		"required": ["kind"],
+		"properties": {"kind": {"enum": ["a", "b"]}},
+		"if": {"properties": {"kind": {"const": "a"}}},
+		"then": {"required": ["a"]},
+		"else": {"required": ["b"]}
+	}`,
+	"recursive": `{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string"},
+			"children": {"type": "array", "items": {"$ref": "#"}}
+		}
+	}`,
+}
+
+func TestGenerate(t *testing.T) {
+	ctx := context.Background()
+	for name, schema := range schemas {
+		t.Run(name, func(t *testing.T) {
+			s, err := jsonschema.CompileString(ctx, name+".json", schema)
+			require.NoError(t, err)
+			for seed := int64(0); seed < 50; seed++ {
+				v, err := generator.New(seed).Generate(s)
+				require.NoError(t, err, "seed %d", seed)
+				assert.NoError(t, s.ValidateInterface(v), "seed %d: %#v", seed, v)
+			}
+		})
+	}
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	s, err := jsonschema.CompileString(context.Background(), "object.json", schemas["recursive"])
+	require.NoError(t, err)
+
+	generate := func(seed int64) string {
+		g := generator.New(seed)
+		var docs []interface{}
+		for i := 0; i < 10; i++ {
+			v, err := g.Generate(s)
+			require.NoError(t, err)
+			docs = append(docs, v)
+		}
+		b, err := json.Marshal(docs)
+		require.NoError(t, err)
+		return string(b)
+	}
+	assert.Equal(t, generate(7), generate(7))
+	assert.NotEqual(t, generate(7), generate(8))
+}
+
+func TestGenerate_Unsatisfiable(t *testing.T) {
+	s, err := jsonschema.CompileString(context.Background(), "unsatisfiable.json", `{"type": "string", "minLength": 5, "maxLength": 2}`)
+	require.NoError(t, err)
+	_, err = generator.New(1).Generate(s)
+	assert.ErrorIs(t, err, generator.ErrUnsatisfiable)
+}
+
+func TestGenerate_DeterministicPatternProperties(t *testing.T) {
+	s, err := jsonschema.CompileString(context.Background(), "patterns.json", `{
+		"type": "object",
+		"required": ["ab"],
+		"patternProperties": {
+			"^a": {"enum": ["x", "y", "z"]},
+			"b$": {"enum": ["z", "y", "x"]},
+			"a": {"enum": ["y", "x", "z"]}
+		}
+	}`)
+	require.NoError(t, err)
+
+	generate := func() string {
+		v, err := generator.New(7).Generate(s)
+		require.NoError(t, err)
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		return string(b)
+	}
+	want := generate()
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, want, generate())
+	}
+}
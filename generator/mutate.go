@@ -0,0 +1,505 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"math/big"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/internal/jsonvalue"
+)
+
+// Mutation is an invalid instance, which violates a single constraint of
+// the schema.
+type Mutation struct {
+	// Instance is the invalid instance.
+	Instance interface{}
+
+	// InstancePtr is json-pointer which refers to the mutated value in
+	// Instance.
+	InstancePtr string
+
+	// SchemaURL and SchemaPtr refer to the keyword, which is expected to
+	// fail. They match SchemaURL and SchemaPtr of a leaf cause of the
+	// *jsonschema.ValidationError returned when validating Instance.
+	SchemaURL string
+	SchemaPtr string
+
+	// Description tells how the valid instance was mutated.
+	Description string
+}
+
+// Mutations returns invalid instances of s, one per constraint, derived from
+// an instance generated by g.
+//
+// Returned error is ErrUnsatisfiable, if no valid instance was found.
+func (g *Generator) Mutations(s *jsonschema.Schema) ([]Mutation, error) {
+	v, err := g.Generate(s)
+	if err != nil {
+		return nil, err
+	}
+	return g.mutate(s, v)
+}
+
+// Mutate returns invalid instances of s, one per constraint of s which
+// applies to the valid instance v. Each instance differs minimally from v, for
+// example a string just over maxLength or an object lacking one required
+// property, and is checked to fail at the constraint it is labelled with.
+//
+// Constraints inside anyOf, oneOf, not and if are not mutated, since no
+// single keyword is expected to fail for them. The mutations are sorted by
+// InstancePtr, SchemaURL and SchemaPtr.
+func Mutate(s *jsonschema.Schema, v interface{}) ([]Mutation, error) {
+	return New(1).mutate(s, v)
+}
+
+func (g *Generator) mutate(s *jsonschema.Schema, v interface{}) ([]Mutation, error) {
+	if err := s.ValidateInterface(v); err != nil {
+		return nil, err
+	}
+	m := &mutator{g: g, root: s, doc: v, seen: make(map[string]bool)}
+	m.mutate(s, s.URL, s.Ptr, v, "#")
+	sort.SliceStable(m.mutations, func(i, j int) bool {
+		mi, mj := m.mutations[i], m.mutations[j]
+		if mi.InstancePtr != mj.InstancePtr {
+			return mi.InstancePtr < mj.InstancePtr
+		}
+		if mi.SchemaURL != mj.SchemaURL {
+			return mi.SchemaURL < mj.SchemaURL
+		}
+		return mi.SchemaPtr < mj.SchemaPtr
+	})
+	return m.mutations, nil
+}
+
+type mutator struct {
+	g         *Generator // generates items added to arrays.
+	root      *jsonschema.Schema
+	doc       interface{}
+	seen      map[string]bool // "instancePtr schemaURL schemaPtr description" of mutations.
+	mutations []Mutation
+}
+
+// candidate is a mutated value, which is tried in place of the valid one.
+type candidate struct {
+	value       interface{}
+	description string
+}
+
+// add records a mutation, replacing the value at instance pointer ip with the
+// first candidate which fails at keyword of the schema at url and ptr.
+// Candidates failing only at that keyword are preferred.
+func (m *mutator) add(url, ptr, keyword, ip string, candidates ...candidate) {
+	schemaPtr := ptr + "/" + keyword
+	if len(candidates) == 0 {
+		return
+	}
+	key := ip + " " + url + " " + schemaPtr + " " + candidates[0].description
+	if m.seen[key] {
+		return
+	}
+	m.seen[key] = true
+
+	var best *Mutation
+	for _, c := range candidates {
+		doc := replace(m.doc, jsonschema.SplitPointer(ip), c.value)
+		leaves := leafErrors(m.root.ValidateInterface(doc))
+		found := false
+		for _, leaf := range leaves {
+			if leaf.SchemaURL == url && leaf.SchemaPtr == schemaPtr {
+				found = true
+			}
+		}
+		if !found {
+			continue
+		}
+		mutation := Mutation{doc, ip, url, schemaPtr, c.description}
+		if len(leaves) == 1 {
+			best = &mutation
+			break
+		}
+		if best == nil {
+			best = &mutation
+		}
+	}
+	if best != nil {
+		m.mutations = append(m.mutations, *best)
+	}
+}
+
+// leafErrors returns the causes of err, which have no causes.
+func leafErrors(err error) []*jsonschema.ValidationError {
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return nil
+	}
+	return verr.Leaves()
+}
+
+// mutate records the mutations of value v at instance pointer ip, for the
+// constraints of schema s at url and ptr.
+func (m *mutator) mutate(s *jsonschema.Schema, url, ptr string, v interface{}, ip string) {
+	for s.Ref != nil {
+		s = s.Ref
+		url, ptr = s.URL, s.Ptr
+	}
+	if s.Always != nil {
+		return
+	}
+
+	if len(s.Types) > 0 {
+		var candidates []candidate
+		for _, t := range types {
+			if !typeAllowed(s.Types, t) {
+				candidates = append(candidates, candidate{sampleValue(t), "value of type " + t})
+			}
+		}
+		m.add(url, ptr, "type", ip, candidates...)
+	}
+	if len(s.Constant) > 0 {
+		m.add(url, ptr, "const", ip, otherValues(v, "value other than const")...)
+	}
+	if s.Enum != nil {
+		var candidates []candidate
+		for _, c := range otherValues(v, "value not in enum") {
+			if !containsValue(s.Enum, c.value) {
+				candidates = append(candidates, c)
+			}
+		}
+		m.add(url, ptr, "enum", ip, candidates...)
+	}
+	for i, sub := range s.AllOf {
+		m.mutate(sub, url, ptr+"/allOf/"+strconv.Itoa(i), v, ip)
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m.mutateObject(s, url, ptr, v, ip)
+	case []interface{}:
+		m.mutateArray(s, url, ptr, v, ip)
+	case string:
+		m.mutateString(s, url, ptr, v, ip)
+	case json.Number, float64, int, int32, int64:
+		m.mutateNumber(s, url, ptr, v, ip)
+	}
+}
+
+func (m *mutator) mutateObject(s *jsonschema.Schema, url, ptr string, v map[string]interface{}, ip string) {
+	for _, pname := range s.Required {
+		if _, ok := v[pname]; ok {
+			m.add(url, ptr, "required", ip, candidate{without(v, pname), "missing required property " + strconv.Quote(pname)})
+		}
+	}
+	if s.MinProperties > 0 && len(v) >= s.MinProperties {
+		// remove the optional properties first.
+		pnames := slices.Sorted(maps.Keys(v))
+		sort.SliceStable(pnames, func(i, j int) bool {
+			return !slices.Contains(s.Required, pnames[i]) && slices.Contains(s.Required, pnames[j])
+		})
+		obj := v
+		for _, pname := range pnames[:len(v)-s.MinProperties+1] {
+			obj = without(obj, pname)
+		}
+		m.add(url, ptr, "minProperties", ip, candidate{obj, "fewer properties than minProperties"})
+	}
+	if s.MaxProperties != -1 {
+		obj := clone(v)
+		for i := 1; len(obj) <= s.MaxProperties; i++ {
+			if _, ok := obj["extra"+strconv.Itoa(i)]; !ok {
+				obj["extra"+strconv.Itoa(i)] = nil
+			}
+		}
+		m.add(url, ptr, "maxProperties", ip, candidate{obj, "more properties than maxProperties"})
+	}
+	if additional, ok := s.AdditionalProperties.(bool); ok && !additional {
+		pname := "unexpected"
+		for i := 1; ; i++ {
+			if _, ok := v[pname]; !ok {
+				break
+			}
+			pname = "unexpected" + strconv.Itoa(i)
+		}
+		obj := clone(v)
+		obj[pname] = nil
+		m.add(url, ptr, "additionalProperties", ip, candidate{obj, "additional property " + strconv.Quote(pname)})
+	}
+	for pname, deps := range s.Dependencies {
+		if deps, ok := deps.([]string); ok {
+			if _, ok := v[pname]; !ok {
+				continue
+			}
+			for _, dep := range deps {
+				if _, ok := v[dep]; ok {
+					m.add(url, ptr, "dependencies", ip, candidate{without(v, dep), "missing property " + strconv.Quote(dep) + " required by " + strconv.Quote(pname)})
+				}
+			}
+		}
+	}
+
+	for _, pname := range slices.Sorted(maps.Keys(v)) {
+		pip := child(ip, pname)
+		if sch, ok := s.Properties[pname]; ok {
+			m.mutate(sch, url, child(ptr, "properties", pname), v[pname], pip)
+		}
+		matched := false
+		for _, re := range sortedPatterns(s.PatternProperties) {
+			if re.MatchString(pname) {
+				matched = true
+				m.mutate(s.PatternProperties[re], url, child(ptr, "patternProperties", re.String()), v[pname], pip)
+			}
+		}
+		if sch, ok := s.AdditionalProperties.(*jsonschema.Schema); ok && !matched && s.Properties[pname] == nil {
+			m.mutate(sch, url, ptr+"/additionalProperties", v[pname], pip)
+		}
+	}
+}
+
+func (m *mutator) mutateArray(s *jsonschema.Schema, url, ptr string, v []interface{}, ip string) {
+	if s.MinItems > 0 && len(v) >= s.MinItems {
+		m.add(url, ptr, "minItems", ip, candidate{v[:s.MinItems-1], "fewer items than minItems"})
+	}
+	if s.MaxItems != -1 && len(v) > 0 {
+		// repeated items are tried first, since they are valid items.
+		repeated := append([]interface{}{}, v...)
+		for len(repeated) <= s.MaxItems {
+			repeated = append(repeated, v[len(repeated)%len(v)])
+		}
+		candidates := []candidate{{repeated, "more items than maxItems"}}
+		if unique := m.uniqueItems(s, v, s.MaxItems+1); unique != nil {
+			candidates = append([]candidate{{unique, "more items than maxItems"}}, candidates...)
+		}
+		m.add(url, ptr, "maxItems", ip, candidates...)
+	}
+	if s.UniqueItems && len(v) > 0 {
+		m.add(url, ptr, "uniqueItems", ip, candidate{append(append([]interface{}{}, v...), v[0]), "duplicate item"})
+	}
+	if s.Contains != nil {
+		var arr []interface{}
+		for _, item := range v {
+			if s.Contains.ValidateInterface(item) != nil {
+				arr = append(arr, item)
+			}
+		}
+		if arr == nil {
+			arr = []interface{}{}
+		}
+		m.add(url, ptr, "contains", ip, candidate{arr, "no item matching contains"})
+	}
+
+	switch items := s.Items.(type) {
+	case *jsonschema.Schema:
+		for i, item := range v {
+			m.mutate(items, url, ptr+"/items", item, ip+"/"+strconv.Itoa(i))
+		}
+	case []*jsonschema.Schema:
+		for i, item := range v {
+			if i < len(items) {
+				m.mutate(items[i], url, ptr+"/items/"+strconv.Itoa(i), item, ip+"/"+strconv.Itoa(i))
+			} else if additional, ok := s.AdditionalItems.(*jsonschema.Schema); ok {
+				m.mutate(additional, url, ptr+"/additionalItems", item, ip+"/"+strconv.Itoa(i))
+			}
+		}
+		if additional, ok := s.AdditionalItems.(bool); ok && !additional && len(v) >= len(items) {
+			m.add(url, ptr, "additionalItems", ip, candidate{append(append([]interface{}{}, v...), nil), "additional item"})
+		}
+	}
+}
+
+// uniqueItems returns v with distinct valid items appended up to n items, or
+// nil if no such items were generated.
+func (m *mutator) uniqueItems(s *jsonschema.Schema, v []interface{}, n int) []interface{} {
+	arr := append([]interface{}{}, v...)
+	for i := len(arr); i < n; i++ {
+		var schemas []*jsonschema.Schema
+		if sch := itemSchema(s, i); sch != nil {
+			schemas = append(schemas, sch)
+		}
+		item, err := m.g.generateUnique(schemas, m.g.MaxDepth, arr, true)
+		if err != nil {
+			return nil
+		}
+		arr = append(arr, item)
+	}
+	return arr
+}
+
+func (m *mutator) mutateString(s *jsonschema.Schema, url, ptr string, v string, ip string) {
+	runes := []rune(v)
+	if s.MinLength > 0 && len(runes) >= s.MinLength {
+		m.add(url, ptr, "minLength", ip, candidate{string(runes[:s.MinLength-1]), "string shorter than minLength"})
+	}
+	if s.MaxLength != -1 {
+		padding := "a"
+		if len(runes) > 0 {
+			padding = string(runes[len(runes)-1])
+		}
+		long := v + strings.Repeat(padding, s.MaxLength+1-len(runes))
+		m.add(url, ptr, "maxLength", ip, candidate{long, "string longer than maxLength"})
+	}
+	if s.Pattern != nil {
+		m.add(url, ptr, "pattern", ip,
+			candidate{v + "!", "string not matching pattern"},
+			candidate{"!" + v, "string not matching pattern"},
+			candidate{"", "string not matching pattern"},
+			candidate{"!", "string not matching pattern"},
+		)
+	}
+	if s.Format != "" && jsonschema.Formats[s.Format] != nil {
+		m.add(url, ptr, "format", ip,
+			candidate{"not a " + s.Format, "string not of format " + s.Format},
+			candidate{"", "string not of format " + s.Format},
+		)
+	}
+}
+
+func (m *mutator) mutateNumber(s *jsonschema.Schema, url, ptr string, v interface{}, ip string) {
+	// steps are tried from the smallest change, so that the mutated number
+	// stays an integer or multiple where possible.
+	var steps []*big.Float
+	if s.MultipleOf != nil {
+		steps = append(steps, s.MultipleOf)
+	}
+	steps = append(steps, big.NewFloat(1), big.NewFloat(0.5))
+	offset := func(bound *big.Float, sign int, description string) []candidate {
+		var candidates []candidate
+		for _, step := range steps {
+			n := new(big.Float).Mul(step, big.NewFloat(float64(sign)))
+			candidates = append(candidates, candidate{number(new(big.Float).Add(bound, n)), description})
+		}
+		return candidates
+	}
+	if s.Minimum != nil {
+		m.add(url, ptr, "minimum", ip, offset(s.Minimum, -1, "number less than minimum")...)
+	}
+	if s.ExclusiveMinimum != nil {
+		m.add(url, ptr, "exclusiveMinimum", ip, candidate{number(s.ExclusiveMinimum), "number equal to exclusiveMinimum"})
+	}
+	if s.Maximum != nil {
+		m.add(url, ptr, "maximum", ip, offset(s.Maximum, 1, "number greater than maximum")...)
+	}
+	if s.ExclusiveMaximum != nil {
+		m.add(url, ptr, "exclusiveMaximum", ip, candidate{number(s.ExclusiveMaximum), "number equal to exclusiveMaximum"})
+	}
+	if s.MultipleOf != nil {
+		num, _ := new(big.Float).SetString(numberString(v))
+		var candidates []candidate
+		for _, d := range []float64{0.5, 0.25, -0.5} {
+			n := new(big.Float).Mul(s.MultipleOf, big.NewFloat(d))
+			candidates = append(candidates, candidate{number(n.Add(n, num)), "number not multipleOf " + s.MultipleOf.Text('g', -1)})
+		}
+		m.add(url, ptr, "multipleOf", ip, candidates...)
+	}
+}
+
+func number(f *big.Float) json.Number {
+	return json.Number(f.Text('g', -1))
+}
+
+func numberString(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func typeAllowed(types []string, t string) bool {
+	for _, typ := range types {
+		if typ == t || (t == "integer" && typ == "number") {
+			return true
+		}
+	}
+	return false
+}
+
+// sampleValue returns a value of json type t.
+func sampleValue(t string) interface{} {
+	switch t {
+	case "string":
+		return "string"
+	case "integer":
+		return json.Number("1")
+	case "number":
+		return json.Number("1.5")
+	case "boolean":
+		return true
+	case "object":
+		return map[string]interface{}{}
+	case "array":
+		return []interface{}{}
+	}
+	return nil
+}
+
+// otherValues returns values other than v, preferring the ones of the same
+// type.
+func otherValues(v interface{}, description string) []candidate {
+	var candidates []candidate
+	switch v := v.(type) {
+	case string:
+		candidates = append(candidates, candidate{v + "x", description})
+	case bool:
+		candidates = append(candidates, candidate{!v, description})
+	case json.Number, float64, int, int32, int64:
+		num, _ := new(big.Float).SetString(numberString(v))
+		candidates = append(candidates, candidate{number(num.Add(num, big.NewFloat(1))), description})
+	}
+	for _, t := range types {
+		if c := sampleValue(t); !jsonvalue.Equal(c, v) {
+			candidates = append(candidates, candidate{c, description})
+		}
+	}
+	return candidates
+}
+
+func containsValue(values []interface{}, v interface{}) bool {
+	for _, value := range values {
+		if jsonvalue.Equal(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// replace returns a copy of doc, with the value at path replaced by v.
+func replace(doc interface{}, path []string, v interface{}) interface{} {
+	if len(path) == 0 {
+		return v
+	}
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		obj := clone(doc)
+		obj[path[0]] = replace(doc[path[0]], path[1:], v)
+		return obj
+	case []interface{}:
+		i, _ := strconv.Atoi(path[0])
+		arr := append([]interface{}{}, doc...)
+		arr[i] = replace(doc[i], path[1:], v)
+		return arr
+	}
+	return doc
+}
+
+// clone returns a shallow copy of obj.
+func clone(obj map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(obj)+1)
+	for k, v := range obj {
+		result[k] = v
+	}
+	return result
+}
+
+// without returns a shallow copy of obj, without property pname.
+func without(obj map[string]interface{}, pname string) map[string]interface{} {
+	result := clone(obj)
+	delete(result, pname)
+	return result
+}
+
+// child returns the json-pointer to the value at tokens within the value at
+// json-pointer ptr.
+func child(ptr string, tokens ...string) string {
+	return jsonschema.JoinPointer(append(jsonschema.SplitPointer(ptr), tokens...))
+}
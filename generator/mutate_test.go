@@ -0,0 +1,109 @@
+package generator_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/generator"
+)
+
+func TestMutate(t *testing.T) {
+	ctx := context.Background()
+	c := jsonschema.NewCompiler()
+	require.NoError(t, c.AddResource("user.json", strings.NewReader(`{
+		"type": "object",
+		"required": ["name", "age"],
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "maxLength": 5},
+			"age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 150},
+			"tags": {"type": "array", "items": {"$ref": "tag.json"}, "maxItems": 2, "uniqueItems": true}
+		}
+	}`)))
+	require.NoError(t, c.AddResource("tag.json", strings.NewReader(`{"enum": ["a", "b", "c"]}`)))
+	s, err := c.Compile(ctx, "user.json")
+	require.NoError(t, err)
+
+	var valid interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"name": "bob", "age": 40, "tags": ["a"]}`), &valid))
+	mutations, err := generator.Mutate(s, valid)
+	require.NoError(t, err)
+
+	var got []string
+	for _, m := range mutations {
+		got = append(got, m.InstancePtr+" "+m.SchemaURL+m.SchemaPtr+" "+m.Description)
+
+		// each mutation fails at the labelled keyword, and only there.
+		err := s.ValidateInterface(m.Instance)
+		require.Error(t, err, m.Description)
+		var leaves []string
+		var walk func(err *jsonschema.ValidationError)
+		walk = func(err *jsonschema.ValidationError) {
+			if len(err.Causes) == 0 {
+				leaves = append(leaves, err.SchemaURL+err.SchemaPtr)
+			}
+			for _, cause := range err.Causes {
+				walk(cause)
+			}
+		}
+		walk(err.(*jsonschema.ValidationError))
+		assert.Equal(t, []string{m.SchemaURL + m.SchemaPtr}, leaves, m.Description)
+	}
+	assert.Equal(t, []string{
+		"# user.json#/additionalProperties additional property \"unexpected\"",
+		"# user.json#/required missing required property \"name\"",
+		"# user.json#/required missing required property \"age\"",
+		"# user.json#/type value of type string",
+		"#/age user.json#/properties/age/exclusiveMaximum number equal to exclusiveMaximum",
+		"#/age user.json#/properties/age/minimum number less than minimum",
+		"#/age user.json#/properties/age/type value of type string",
+		"#/name user.json#/properties/name/maxLength string longer than maxLength",
+		"#/name user.json#/properties/name/minLength string shorter than minLength",
+		"#/name user.json#/properties/name/type value of type integer",
+		"#/tags user.json#/properties/tags/maxItems more items than maxItems",
+		"#/tags user.json#/properties/tags/type value of type string",
+		"#/tags user.json#/properties/tags/uniqueItems duplicate item",
+		"#/tags/0 tag.json#/enum value not in enum",
+	}, got)
+}
+
+func TestMutations(t *testing.T) {
+	ctx := context.Background()
+	for name, schema := range schemas {
+		t.Run(name, func(t *testing.T) {
+			s, err := jsonschema.CompileString(ctx, name+".json", schema)
+			require.NoError(t, err)
+			mutations, err := generator.New(1).Mutations(s)
+			require.NoError(t, err)
+			for _, m := range mutations {
+				assert.Error(t, s.ValidateInterface(m.Instance), "%s: %s", m.SchemaPtr, m.Description)
+			}
+		})
+	}
+}
+
+func TestMutate_EscapedProperty(t *testing.T) {
+	s, err := jsonschema.CompileString(context.Background(), "test.json", `{
+		"properties": {"a/b~c": {"enum": [1, 2]}}
+	}`)
+	require.NoError(t, err)
+	valid, err := jsonschema.DecodeJSON(strings.NewReader(`{"a/b~c": 1.0}`))
+	require.NoError(t, err)
+	mutations, err := generator.Mutate(s, valid)
+	require.NoError(t, err)
+
+	var got []string
+	for _, m := range mutations {
+		got = append(got, m.InstancePtr+" "+m.SchemaPtr+" "+m.Description)
+		assert.Error(t, s.ValidateInterface(m.Instance), m.Description)
+	}
+	assert.Equal(t, []string{
+		"#/a~1b~0c #/properties/a~1b~0c/enum value not in enum",
+	}, got)
+}
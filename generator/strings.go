@@ -0,0 +1,133 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp/syntax"
+	"strings"
+	"time"
+)
+
+// formats maps format names to functions generating strings of that format.
+var formats = map[string]func(r *rand.Rand) string{
+	"date-time":  func(r *rand.Rand) string { return randomTime(r).Format(time.RFC3339) },
+	"date":       func(r *rand.Rand) string { return randomTime(r).Format("2006-01-02") },
+	"time":       func(r *rand.Rand) string { return randomTime(r).Format("15:04:05Z07:00") },
+	"hostname":   func(r *rand.Rand) string { return fmt.Sprintf("host%d.example.com", r.Intn(1000)) },
+	"email":      func(r *rand.Rand) string { return fmt.Sprintf("user%d@example.com", r.Intn(1000)) },
+	"tel":        func(r *rand.Rand) string { return fmt.Sprintf("+1 650-253-%04d", r.Intn(10000)) },
+	"ipv4":       randomIPv4,
+	"ip-address": randomIPv4,
+	"ipv6": func(r *rand.Rand) string {
+		return fmt.Sprintf("2001:db8::%x:%x", r.Intn(0x10000), r.Intn(0x10000))
+	},
+	"uri":           randomURI,
+	"iri":           randomURI,
+	"uri-reference": randomPath,
+	"uriref":        randomPath,
+	"iri-reference": randomPath,
+	"uri-template": func(r *rand.Rand) string {
+		return fmt.Sprintf("https://example.com/items%d/{id}", r.Intn(1000))
+	},
+	"regex":                 func(r *rand.Rand) string { return fmt.Sprintf("^[a-z]{%d}$", 1+r.Intn(10)) },
+	"json-pointer":          func(r *rand.Rand) string { return fmt.Sprintf("/items/%d", r.Intn(100)) },
+	"relative-json-pointer": func(r *rand.Rand) string { return fmt.Sprintf("%d/items", r.Intn(10)) },
+	"uuid": func(r *rand.Rand) string {
+		b := make([]byte, 16)
+		r.Read(b)
+		b[6], b[8] = b[6]&0x0f|0x40, b[8]&0x3f|0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	},
+}
+
+func randomTime(r *rand.Rand) time.Time {
+	return time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(r.Int63n(int64(30 * 365 * 24 * time.Hour))))
+}
+
+func randomIPv4(r *rand.Rand) string {
+	return fmt.Sprintf("192.0.2.%d", r.Intn(256))
+}
+
+func randomURI(r *rand.Rand) string {
+	return "https://example.com" + randomPath(r)
+}
+
+func randomPath(r *rand.Rand) string {
+	return fmt.Sprintf("/items/%d", r.Intn(1000))
+}
+
+// maxRepeat bounds the repetitions of *, + and unbounded {n,}.
+const maxRepeat = 3
+
+// pattern returns a string, which matches the regular expression expr.
+func (g *Generator) pattern(expr string) (string, error) {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := g.regexp(&b, re.Simplify()); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func (g *Generator) regexp(b *strings.Builder, re *syntax.Regexp) error {
+	repeat := func(min, max int) error {
+		if max == -1 {
+			max = min + maxRepeat
+		}
+		for n := min + g.rand.Intn(max-min+1); n > 0; n-- {
+			if err := g.regexp(b, re.Sub[0]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return ErrUnsatisfiable
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		b.WriteRune(g.charClass(re.Rune))
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		b.WriteByte(alphanumeric[g.rand.Intn(len(alphanumeric))])
+	case syntax.OpCapture:
+		return g.regexp(b, re.Sub[0])
+	case syntax.OpStar:
+		return repeat(0, -1)
+	case syntax.OpPlus:
+		return repeat(1, -1)
+	case syntax.OpQuest:
+		return repeat(0, 1)
+	case syntax.OpRepeat:
+		return repeat(re.Min, re.Max)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if err := g.regexp(b, sub); err != nil {
+				return err
+			}
+		}
+	case syntax.OpAlternate:
+		return g.regexp(b, re.Sub[g.rand.Intn(len(re.Sub))])
+	}
+	// empty matches, such as anchors and word boundaries, generate nothing.
+	return nil
+}
+
+// charClass returns a rune of the class given as ranges, preferring
+// printable ascii.
+func (g *Generator) charClass(ranges []rune) rune {
+	var printable []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for c := max(ranges[i], ' '); c <= min(ranges[i+1], '~'); c++ {
+			printable = append(printable, c)
+		}
+	}
+	if len(printable) > 0 {
+		return printable[g.rand.Intn(len(printable))]
+	}
+	i := 2 * g.rand.Intn(len(ranges)/2)
+	return ranges[i]
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/hashicorp/go-retryablehttp"
 
 	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/cacheloader"
 )
 
 type key string
@@ -43,17 +44,34 @@ func Load(ctx context.Context, url string) (io.ReadCloser, error) {
 		return nil, err
 	}
 	req = req.WithContext(ctx)
+	if etag := cacheloader.IfNoneMatch(ctx); etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
 	resp, err := hc.Do(req)
 	if err != nil {
 		return nil, err
 	}
 
+	if resp.StatusCode == http.StatusNotModified {
+		_ = resp.Body.Close()
+		return nil, cacheloader.ErrNotModified
+	}
 	if resp.StatusCode != http.StatusOK {
 		_ = resp.Body.Close()
 		return nil, fmt.Errorf("%s returned status code %d", url, resp.StatusCode)
 	}
 
-	return resp.Body, nil
+	return &body{resp.Body, resp.Header.Get("ETag")}, nil
+}
+
+// body exposes the entity tag of the response to cacheloader.Cache.
+type body struct {
+	io.ReadCloser
+	etag string
+}
+
+func (b *body) ETag() string {
+	return b.etag
 }
 
 func init() {
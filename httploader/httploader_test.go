@@ -7,10 +7,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ory/jsonschema/v3/cacheloader"
 )
 
 var errFoo = errors.New("foo")
@@ -50,3 +53,34 @@ func TestHTTPLoader(t *testing.T) {
 	_, err = Load(context.WithValue(context.Background(), ContextKey, new(struct{})), ts.URL)
 	assert.ErrorContains(t, err, "invalid context value for github.com/ory/jsonschema/v3/httploader.HTTPClient expected *retryablehttp.Client but got: *struct {}")
 }
+
+func TestHTTPLoaderRevalidation(t *testing.T) {
+	var notModified int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	ctx := context.WithValue(context.Background(), ContextKey, retryablehttp.NewClient())
+	res, err := Load(ctx, ts.URL)
+	require.NoError(t, err)
+	_ = res.Close()
+	assert.Equal(t, `"v1"`, res.(interface{ ETag() string }).ETag())
+
+	c := &cacheloader.Cache{TTL: time.Nanosecond, Next: Load}
+	for i := 0; i < 2; i++ {
+		time.Sleep(time.Millisecond)
+		res, err := c.Load(ctx, ts.URL)
+		require.NoError(t, err)
+		body, err := io.ReadAll(res)
+		require.NoError(t, err)
+		assert.Equal(t, `{}`, string(body))
+	}
+	assert.Equal(t, 1, notModified)
+}
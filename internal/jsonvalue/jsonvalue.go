@@ -0,0 +1,90 @@
+// Package jsonvalue provides helpers for json values, as decoded by
+// jsonschema.DecodeJSON.
+package jsonvalue
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// Type returns the json type of given value v, or "" if v is not a valid
+// json value.
+func Type(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64, int, int32, int64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return ""
+}
+
+// Equal tells if given two json values are equal or not. Numbers are equal,
+// if their values are equal, regardless of their go types.
+func Equal(v1, v2 interface{}) bool {
+	v1Type := Type(v1)
+	if v1Type != Type(v2) {
+		return false
+	}
+	switch v1Type {
+	case "array":
+		arr1, arr2 := v1.([]interface{}), v2.([]interface{})
+		if len(arr1) != len(arr2) {
+			return false
+		}
+		for i := range arr1 {
+			if !Equal(arr1[i], arr2[i]) {
+				return false
+			}
+		}
+		return true
+	case "object":
+		obj1, obj2 := v1.(map[string]interface{}), v2.(map[string]interface{})
+		if len(obj1) != len(obj2) {
+			return false
+		}
+		for k, v1 := range obj1 {
+			if v2, ok := obj2[k]; !ok || !Equal(v1, v2) {
+				return false
+			}
+		}
+		return true
+	case "number":
+		num1, ok1 := new(big.Float).SetString(fmt.Sprint(v1))
+		num2, ok2 := new(big.Float).SetString(fmt.Sprint(v2))
+		return ok1 && ok2 && num1.Cmp(num2) == 0
+	case "":
+		return false
+	default:
+		return v1 == v2
+	}
+}
+
+// Copy returns a deep copy of given json value v.
+func Copy(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[k] = Copy(item)
+		}
+		return m
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, item := range v {
+			arr[i] = Copy(item)
+		}
+		return arr
+	default:
+		return v
+	}
+}
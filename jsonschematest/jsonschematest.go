@@ -0,0 +1,201 @@
+// Package jsonschematest provides assertions for testing json-schemas and
+// the errors they report, without matching substrings of error messages.
+//
+//	func TestUser(t *testing.T) {
+//		s := jsonschema.MustCompile(ctx, "user.json")
+//		jsonschematest.AssertValid(t, s, `{"name": "alice"}`)
+//		jsonschematest.AssertInvalidAt(t, s, `{"name": 1}`, "#/name", "type")
+//		jsonschematest.AssertGolden(t, s.ValidateInterface(doc), "testdata/user.golden")
+//	}
+//
+// Documents are given either as json text, with type string or []byte, or as
+// any other value, which is encoded to json first. This allows passing the
+// structs, which are sent over the wire.
+package jsonschematest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+// TestingT is the subset of testing.TB used by the assertions.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Update tells AssertGolden to write golden files instead of comparing them.
+// It is set, if environment variable UPDATE_GOLDEN is not empty.
+var Update = os.Getenv("UPDATE_GOLDEN") != ""
+
+// decode returns doc as value decoded by jsonschema.DecodeJSON.
+func decode(doc interface{}) (interface{}, error) {
+	var data []byte
+	switch doc := doc.(type) {
+	case string:
+		data = []byte(doc)
+	case []byte:
+		data = doc
+	default:
+		b, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		data = b
+	}
+	return jsonschema.DecodeJSON(bytes.NewReader(data))
+}
+
+// decodeDoc returns doc decoded. It reports an error to t, if doc cannot be
+// decoded.
+func decodeDoc(t TestingT, doc interface{}) (interface{}, bool) {
+	t.Helper()
+	v, err := decode(doc)
+	if err != nil {
+		t.Errorf("invalid json document: %v", err)
+		return nil, false
+	}
+	return v, true
+}
+
+// AssertValid asserts that doc is valid against s.
+func AssertValid(t TestingT, s *jsonschema.Schema, doc interface{}) bool {
+	t.Helper()
+	v, ok := decodeDoc(t, doc)
+	if !ok {
+		return false
+	}
+	if err := s.ValidateInterface(v); err != nil {
+		t.Errorf("expected document to be valid against %s%s, got:\n%v", s.URL, s.Ptr, err)
+		return false
+	}
+	return true
+}
+
+// AssertInvalid asserts that doc is not valid against s, and returns the
+// validation error.
+func AssertInvalid(t TestingT, s *jsonschema.Schema, doc interface{}) *jsonschema.ValidationError {
+	t.Helper()
+	v, ok := decodeDoc(t, doc)
+	if !ok {
+		return nil
+	}
+	err := s.ValidateInterface(v)
+	if err == nil {
+		t.Errorf("expected document to be invalid against %s%s", s.URL, s.Ptr)
+		return nil
+	}
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		t.Errorf("expected *jsonschema.ValidationError, got %T: %v", err, err)
+		return nil
+	}
+	return verr
+}
+
+// AssertInvalidAt asserts that doc is not valid against s, and that the
+// validation error or any of its causes reports keyword, such as "minLength",
+// failing at instancePtr, such as "#/name".
+func AssertInvalidAt(t TestingT, s *jsonschema.Schema, doc interface{}, instancePtr, keyword string) bool {
+	t.Helper()
+	verr := AssertInvalid(t, s, doc)
+	if verr == nil {
+		return false
+	}
+	if Find(verr, instancePtr, keyword) == nil {
+		t.Errorf("expected keyword %q to fail at %s, got:\n%s", keyword, instancePtr, Render(verr))
+		return false
+	}
+	return true
+}
+
+// Find returns the first error of the tree rooted at err, in depth-first
+// order, which reports keyword failing at instancePtr.
+func Find(err *jsonschema.ValidationError, instancePtr, keyword string) *jsonschema.ValidationError {
+	for _, found := range err.ByKeyword(keyword) {
+		if found.InstancePtr == instancePtr {
+			return found
+		}
+	}
+	return nil
+}
+
+// Render returns the error tree rooted at err as text, with one line per
+// error in the format "I[<instancePtr>] S[<schemaURL><schemaPtr>] <message>"
+// and causes indented. Causes are sorted by InstancePtr, SchemaURL and
+// SchemaPtr, so that the text is stable.
+func Render(err *jsonschema.ValidationError) string {
+	var b strings.Builder
+	render(&b, err, "")
+	return b.String()
+}
+
+func render(b *strings.Builder, err *jsonschema.ValidationError, indent string) {
+	fmt.Fprintf(b, "%sI[%s] S[%s%s] %s\n", indent, err.InstancePtr, err.SchemaURL, err.SchemaPtr, err.Message)
+	causes := append([]*jsonschema.ValidationError(nil), err.Causes...)
+	sort.SliceStable(causes, func(i, j int) bool {
+		ci, cj := causes[i], causes[j]
+		if ci.InstancePtr != cj.InstancePtr {
+			return ci.InstancePtr < cj.InstancePtr
+		}
+		if ci.SchemaURL != cj.SchemaURL {
+			return ci.SchemaURL < cj.SchemaURL
+		}
+		if ci.SchemaPtr != cj.SchemaPtr {
+			return ci.SchemaPtr < cj.SchemaPtr
+		}
+		return ci.Message < cj.Message
+	})
+	for _, cause := range causes {
+		render(b, cause, indent+"  ")
+	}
+}
+
+// AssertGolden asserts that err, rendered by Render, equals the content of
+// the golden file at path. err must be *jsonschema.ValidationError or nil,
+// which is rendered as empty text.
+//
+// If Update is set, the golden file is written instead.
+func AssertGolden(t TestingT, err error, path string) bool {
+	t.Helper()
+	var got string
+	if err != nil {
+		var verr *jsonschema.ValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("expected *jsonschema.ValidationError, got %T: %v", err, err)
+			return false
+		}
+		got = Render(verr)
+	}
+
+	if Update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("updating golden file: %v", err)
+			return false
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Errorf("updating golden file: %v", err)
+			return false
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("reading golden file: %v (set UPDATE_GOLDEN=1 to create it)", err)
+		return false
+	}
+	if got != string(want) {
+		t.Errorf("validation error differs from golden file %s:\ngot:\n%s\nwant:\n%s", path, got, want)
+		return false
+	}
+	return true
+}
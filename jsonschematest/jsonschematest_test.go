@@ -0,0 +1,135 @@
+package jsonschematest_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/jsonschematest"
+)
+
+// recorder records the errors reported by the assertions.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func compile(t *testing.T) *jsonschema.Schema {
+	s, err := jsonschema.CompileString(context.Background(), "user.json", `{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string", "minLength": 2},
+			"age": {"type": "integer", "minimum": 0}
+		}
+	}`)
+	require.NoError(t, err)
+	return s
+}
+
+type user struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestAssertValid(t *testing.T) {
+	s := compile(t)
+
+	r := &recorder{}
+	assert.True(t, jsonschematest.AssertValid(r, s, `{"name": "alice"}`))
+	assert.True(t, jsonschematest.AssertValid(r, s, []byte(`{"name": "bob", "age": 3}`)))
+	assert.True(t, jsonschematest.AssertValid(r, s, user{Name: "carol", Age: 30}))
+	assert.Empty(t, r.errors)
+
+	assert.False(t, jsonschematest.AssertValid(r, s, user{Name: "c", Age: 30}))
+	assert.False(t, jsonschematest.AssertValid(r, s, `{"name": `))
+	require.Len(t, r.errors, 2)
+	assert.Contains(t, r.errors[0], "expected document to be valid against user.json#")
+	assert.Contains(t, r.errors[1], "invalid json document")
+}
+
+func TestAssertInvalidAt(t *testing.T) {
+	s := compile(t)
+
+	r := &recorder{}
+	assert.True(t, jsonschematest.AssertInvalidAt(r, s, `{"name": "a"}`, "#/name", "minLength"))
+	assert.True(t, jsonschematest.AssertInvalidAt(r, s, user{Name: "alice", Age: -1}, "#/age", "minimum"))
+	assert.True(t, jsonschematest.AssertInvalidAt(r, s, `{}`, "#", "required"))
+	assert.Empty(t, r.errors)
+
+	assert.False(t, jsonschematest.AssertInvalidAt(r, s, `{"name": "a"}`, "#/name", "type"))
+	assert.False(t, jsonschematest.AssertInvalidAt(r, s, `{"name": "alice"}`, "#/name", "minLength"))
+	require.Len(t, r.errors, 2)
+	assert.Contains(t, r.errors[0], `expected keyword "type" to fail at #/name, got:`)
+	assert.Contains(t, r.errors[0], "I[#/name] S[user.json#/properties/name/minLength]")
+	assert.Contains(t, r.errors[1], "expected document to be invalid against user.json#")
+}
+
+func TestFind(t *testing.T) {
+	s := compile(t)
+	verr := jsonschematest.AssertInvalid(t, s, `{"name": "a", "age": 1.5}`)
+	require.NotNil(t, verr)
+
+	found := jsonschematest.Find(verr, "#/age", "type")
+	require.NotNil(t, found)
+	assert.Equal(t, "#/properties/age/type", found.SchemaPtr)
+	assert.Nil(t, jsonschematest.Find(verr, "#/age", "minimum"))
+}
+
+func TestRender(t *testing.T) {
+	s := compile(t)
+	verr := jsonschematest.AssertInvalid(t, s, `{"name": "a", "age": -1}`)
+	require.NotNil(t, verr)
+
+	// causes are sorted, regardless of the order of validation
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, `I[#] S[user.json#] validation failed
+  I[#/age] S[user.json#/properties/age/minimum] must be >= 0 but found -1
+  I[#/name] S[user.json#/properties/name/minLength] length must be >= 2, but got 1
+`, jsonschematest.Render(jsonschematest.AssertInvalid(t, s, `{"name": "a", "age": -1}`)))
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	s := compile(t)
+	err := s.ValidateInterface(map[string]interface{}{})
+
+	assert.True(t, jsonschematest.AssertGolden(t, err, "testdata/required.golden"))
+	assert.True(t, jsonschematest.AssertGolden(t, nil, "testdata/valid.golden"))
+
+	r := &recorder{}
+	assert.False(t, jsonschematest.AssertGolden(r, err, "testdata/valid.golden"))
+	assert.False(t, jsonschematest.AssertGolden(r, err, "testdata/missing.golden"))
+	require.Len(t, r.errors, 2)
+	assert.Contains(t, r.errors[0], "validation error differs from golden file testdata/valid.golden")
+	assert.Contains(t, r.errors[1], "reading golden file")
+}
+
+func TestAssertGoldenUpdate(t *testing.T) {
+	s := compile(t)
+	err := s.ValidateInterface(map[string]interface{}{})
+	path := filepath.Join(t.TempDir(), "update", "required.golden")
+
+	defer func(update bool) { jsonschematest.Update = update }(jsonschematest.Update)
+	jsonschematest.Update = true
+	require.True(t, jsonschematest.AssertGolden(t, err, path))
+	jsonschematest.Update = false
+	assert.True(t, jsonschematest.AssertGolden(t, err, path))
+
+	got, rerr := os.ReadFile(path)
+	require.NoError(t, rerr)
+	want, rerr := os.ReadFile("testdata/required.golden")
+	require.NoError(t, rerr)
+	assert.Equal(t, string(want), string(got))
+}
@@ -0,0 +1,99 @@
+package jsonschema
+
+import (
+	"sort"
+	"strings"
+)
+
+// keywords returns the keywords compiled by Compiler for schemas of given draft.
+func keywords(draft *Draft) map[string]bool {
+	kw := map[string]bool{
+		"$schema": true, draft.id: true, "$ref": true,
+		"title": true, "description": true, "default": true, "format": true,
+		"type": true, "enum": true, "not": true, "allOf": true, "anyOf": true, "oneOf": true,
+		"multipleOf": true, "maximum": true, "exclusiveMaximum": true, "minimum": true, "exclusiveMinimum": true,
+		"maxLength": true, "minLength": true, "pattern": true,
+		"items": true, "additionalItems": true, "maxItems": true, "minItems": true, "uniqueItems": true,
+		"maxProperties": true, "minProperties": true, "required": true, "definitions": true,
+		"properties": true, "patternProperties": true, "additionalProperties": true, "dependencies": true,
+		"regexProperties": true,
+		// readOnly and writeOnly are enforced for every draft, see WithDirection.
+		"readOnly": true, "writeOnly": true,
+	}
+	if draft.version >= 6 {
+		for _, k := range []string{"const", "contains", "propertyNames", "examples"} {
+			kw[k] = true
+		}
+	}
+	if draft.version >= 7 {
+		for _, k := range []string{"$comment", "if", "then", "else", "contentEncoding", "contentMediaType"} {
+			kw[k] = true
+		}
+	}
+	return kw
+}
+
+// draftOfKeyword maps the keywords introduced by drafts after draft4 to the
+// name of the draft introducing them.
+var draftOfKeyword = map[string]string{
+	"$id": "draft6", "const": "draft6", "contains": "draft6", "propertyNames": "draft6", "examples": "draft6",
+	"$comment": "draft7", "if": "draft7", "then": "draft7", "else": "draft7",
+	"contentEncoding": "draft7", "contentMediaType": "draft7",
+	"$defs": "draft 2019-09", "$anchor": "draft 2019-09", "$recursiveRef": "draft 2019-09", "$recursiveAnchor": "draft 2019-09",
+	"$vocabulary": "draft 2019-09", "dependentRequired": "draft 2019-09", "dependentSchemas": "draft 2019-09",
+	"unevaluatedItems": "draft 2019-09", "unevaluatedProperties": "draft 2019-09", "minContains": "draft 2019-09",
+	"maxContains": "draft 2019-09", "deprecated": "draft 2019-09", "contentSchema": "draft 2019-09",
+	"prefixItems": "draft 2020-12", "$dynamicRef": "draft 2020-12", "$dynamicAnchor": "draft 2020-12",
+}
+
+// knownKeywords returns the keywords of schemas of given draft, which are
+// compiled by c or described by the metaschemas of its extensions.
+func (c *Compiler) knownKeywords(draft *Draft) map[string]bool {
+	kw := keywords(draft)
+	if c.ErrorMessages {
+		kw["errorMessage"] = true
+	}
+	var collect func(s *Schema)
+	collect = func(s *Schema) {
+		if s == nil {
+			return
+		}
+		for pname := range s.Properties {
+			kw[pname] = true
+		}
+		collect(s.Ref)
+		for _, sch := range s.AllOf {
+			collect(sch)
+		}
+	}
+	for _, ext := range c.Extensions {
+		collect(ext.Meta)
+	}
+	return kw
+}
+
+// unknownKeywords returns the sorted keywords of schema m, which are neither
+// known, as returned by knownKeywords, nor allowed by AllowedKeywords.
+func (c *Compiler) unknownKeywords(known map[string]bool, m map[string]interface{}) []string {
+	var unknown []string
+	for pname := range m {
+		if !known[pname] && !c.allowedKeyword(pname) {
+			unknown = append(unknown, pname)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+func (c *Compiler) allowedKeyword(keyword string) bool {
+	for _, allowed := range c.AllowedKeywords {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok {
+			if strings.HasPrefix(keyword, prefix) {
+				return true
+			}
+		} else if keyword == allowed {
+			return true
+		}
+	}
+	return false
+}
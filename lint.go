@@ -0,0 +1,275 @@
+package jsonschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Severity tells how serious a LintFinding is.
+type Severity string
+
+const (
+	// SeverityError is used for mistakes which make the schema behave
+	// differently from what its author obviously intended.
+	SeverityError Severity = "error"
+
+	// SeverityWarning is used for parts of the schema which have no effect.
+	SeverityWarning Severity = "warning"
+)
+
+// LintFinding describes a schema smell reported by Compiler.Lint.
+type LintFinding struct {
+	// SchemaURL is the url of the linted json-schema.
+	SchemaURL string
+
+	// SchemaPtr is json-pointer which refers to the schema in which the
+	// smell was found.
+	SchemaPtr string
+
+	// Rule is the name of the check which reported the finding,
+	// such as "unknown-keyword".
+	Rule string
+
+	Severity Severity
+
+	// Message describes the finding.
+	Message string
+}
+
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s: %s%s: %s [%s]", f.Severity, f.SchemaURL, f.SchemaPtr, f.Message, f.Rule)
+}
+
+// Lint reports the smells of the json-schema at given url, which its
+// meta-schema cannot catch. The returned findings are sorted by SchemaPtr.
+//
+// The schema is compiled first, and the returned error can be *SchemaError.
+func (c *Compiler) Lint(ctx context.Context, url string) ([]LintFinding, error) {
+	if _, err := c.Compile(ctx, url); err != nil {
+		return nil, err
+	}
+	base, _ := split(url)
+	r, err := c.resource(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []LintFinding
+	report := func(ptr, rule string, severity Severity, format string, a ...interface{}) {
+		findings = append(findings, LintFinding{
+			SchemaURL: r.url,
+			SchemaPtr: "#" + ptr,
+			Rule:      rule,
+			Severity:  severity,
+			Message:   fmt.Sprintf(format, a...),
+		})
+	}
+
+	known := c.knownKeywords(r.draft)
+	err = walkSchemas(r.draft, r.url, "", r.doc, func(_, ptr string, m map[string]interface{}) error {
+		for _, pname := range c.unknownKeywords(known, m) {
+			switch {
+			case pname == "id" && r.draft.version >= 6:
+				report(ptr, "draft-mismatch", SeverityWarning, "keyword %q is replaced by %q since draft6", pname, r.draft.id)
+			case draftOfKeyword[pname] != "":
+				report(ptr, "draft-mismatch", SeverityWarning, "keyword %q requires %s, but schema is draft%d", pname, draftOfKeyword[pname], r.draft.version)
+			default:
+				if s := suggest(pname, known); s != "" {
+					report(ptr, "unknown-keyword", SeverityWarning, "unknown keyword %q, did you mean %q?", pname, s)
+				} else {
+					report(ptr, "unknown-keyword", SeverityWarning, "unknown keyword %q", pname)
+				}
+			}
+		}
+
+		if _, ok := m["$ref"]; ok {
+			var ignored []string
+			for pname := range m {
+				switch pname {
+				case "$ref", "$schema", "$comment", r.draft.id, "definitions":
+				default:
+					ignored = append(ignored, strconv.Quote(pname))
+				}
+			}
+			if len(ignored) > 0 {
+				sort.Strings(ignored)
+				if len(ignored) == 1 {
+					report(ptr, "ref-siblings", SeverityWarning, "keyword %s is ignored next to \"$ref\"", ignored[0])
+				} else {
+					report(ptr, "ref-siblings", SeverityWarning, "keywords %s are ignored next to \"$ref\"", strings.Join(ignored, ", "))
+				}
+			}
+			return nil
+		}
+
+		lintRequired(ptr, m, report)
+		lintBounds(ptr, m, report)
+		if r.draft.version >= 7 {
+			lintConditional(ptr, m, report)
+		}
+		return c.lintExamples(ctx, r, ptr, m, report)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].SchemaPtr < findings[j].SchemaPtr })
+	return findings, nil
+}
+
+type reportFunc func(ptr, rule string, severity Severity, format string, a ...interface{})
+
+// lintRequired reports required properties, which additionalProperties forbids.
+func lintRequired(ptr string, m map[string]interface{}, report reportFunc) {
+	if additional, ok := m["additionalProperties"].(bool); !ok || additional {
+		return
+	}
+	required, _ := m["required"].([]interface{})
+	props, _ := m["properties"].(map[string]interface{})
+	patterns, _ := m["patternProperties"].(map[string]interface{})
+outer:
+	for _, pname := range required {
+		pname, ok := pname.(string)
+		if !ok {
+			continue
+		}
+		if _, ok := props[pname]; ok {
+			continue
+		}
+		for pattern := range patterns {
+			if re, err := regexp.Compile(pattern); err == nil && re.MatchString(pname) {
+				continue outer
+			}
+		}
+		report(ptr, "required-not-allowed", SeverityError, "required property %q is not allowed by \"additionalProperties\": false", pname)
+	}
+}
+
+// lintBounds reports lower bounds exceeding their upper bounds.
+func lintBounds(ptr string, m map[string]interface{}, report reportFunc) {
+	num := func(pname string) *big.Float {
+		if n, ok := m[pname].(json.Number); ok {
+			f, _ := new(big.Float).SetString(string(n))
+			return f
+		}
+		return nil
+	}
+	for _, pair := range [][2]string{
+		{"minimum", "maximum"},
+		{"exclusiveMinimum", "maximum"},
+		{"minimum", "exclusiveMaximum"},
+		{"exclusiveMinimum", "exclusiveMaximum"},
+		{"minLength", "maxLength"},
+		{"minItems", "maxItems"},
+		{"minProperties", "maxProperties"},
+	} {
+		min, max := num(pair[0]), num(pair[1])
+		if min == nil || max == nil {
+			continue
+		}
+		exclusive := strings.HasPrefix(pair[0], "exclusive") || strings.HasPrefix(pair[1], "exclusive")
+		if cmp := min.Cmp(max); cmp > 0 || (cmp == 0 && exclusive) {
+			report(ptr, "impossible-bounds", SeverityError, "no value satisfies %q %v and %q %v", pair[0], min, pair[1], max)
+		}
+	}
+}
+
+// lintConditional reports "if", "then" and "else" branches, which can never
+// be applied.
+func lintConditional(ptr string, m map[string]interface{}, report reportFunc) {
+	iff, hasIf := m["if"]
+	_, hasThen := m["then"]
+	_, hasElse := m["else"]
+	switch {
+	case !hasIf && (hasThen || hasElse):
+		report(ptr, "unreachable-branch", SeverityWarning, "\"then\" and \"else\" are ignored without \"if\"")
+	case hasIf && !hasThen && !hasElse:
+		report(ptr, "unreachable-branch", SeverityWarning, "\"if\" is ignored without \"then\" or \"else\"")
+	case hasIf:
+		always := iff == true
+		if m, ok := iff.(map[string]interface{}); ok && len(m) == 0 {
+			always = true
+		}
+		if always && hasElse {
+			report(ptr, "unreachable-branch", SeverityWarning, "\"else\" is never applied, because \"if\" always passes")
+		}
+		if iff == false && hasThen {
+			report(ptr, "unreachable-branch", SeverityWarning, "\"then\" is never applied, because \"if\" always fails")
+		}
+	}
+}
+
+// lintExamples reports "default" and "examples" values, which are not valid
+// against their own schema.
+func (c *Compiler) lintExamples(ctx context.Context, r *resource, ptr string, m map[string]interface{}, report reportFunc) error {
+	values := make(map[string]interface{})
+	if v, ok := m["default"]; ok {
+		values["/default"] = v
+	}
+	if examples, ok := m["examples"].([]interface{}); ok && r.draft.version >= 6 {
+		for i, v := range examples {
+			values["/examples/"+strconv.Itoa(i)] = v
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	s, err := c.compileRef(ctx, r, r.url, "#"+ptr)
+	if err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(values) {
+		if err := s.ValidateInterface(values[name]); err != nil {
+			report(ptr, "invalid-example", SeverityError, "%q is not valid against its schema: %s", strings.TrimPrefix(name, "/"), leafMessage(err))
+		}
+	}
+	return nil
+}
+
+// leafMessage returns the message of the first leaf cause of err.
+func leafMessage(err error) string {
+	ve, ok := err.(*ValidationError)
+	if !ok {
+		return err.Error()
+	}
+	for len(ve.Causes) > 0 {
+		ve = ve.Causes[0]
+	}
+	return ve.Message
+}
+
+// suggest returns the keyword most similar to s, if it is likely a typo of it.
+func suggest(s string, keywords map[string]bool) string {
+	best, bestDist := "", 3
+	for _, k := range sortedKeys(keywords) {
+		if d := levenshtein(strings.ToLower(s), strings.ToLower(k)); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,82 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+func TestLint(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	findings, err := c.Lint(ctx, "testdata/lint/smells.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.SchemaPtr+" "+string(f.Severity)+" "+f.Rule)
+		t.Log(f)
+	}
+	expected := []string{
+		"# error required-not-allowed",
+		"#/definitions/id error impossible-bounds",
+		"#/properties/age error impossible-bounds",
+		"#/properties/code warning draft-mismatch",
+		"#/properties/flag warning unreachable-branch",
+		"#/properties/id warning ref-siblings",
+		"#/properties/kind warning unreachable-branch",
+		"#/properties/name warning unknown-keyword",
+		"#/properties/name error invalid-example",
+		"#/properties/tags warning unknown-keyword",
+		"#/properties/tags error invalid-example",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+
+	for _, f := range findings {
+		if f.SchemaPtr == "#/properties/name" && f.Rule == "unknown-keyword" && !strings.Contains(f.Message, `did you mean "maxLength"?`) {
+			t.Errorf("expected suggestion in %q", f.Message)
+		}
+	}
+
+	t.Run("draft4", func(t *testing.T) {
+		c := jsonschema.NewCompiler()
+		if err := c.AddResource("test.json", strings.NewReader(`{
+			"$schema": "http://json-schema.org/draft-04/schema#",
+			"properties": {"a": {"const": 1}}
+		}`)); err != nil {
+			t.Fatal(err)
+		}
+		findings, err := c.Lint(ctx, "test.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(findings) != 1 || findings[0].Rule != "draft-mismatch" {
+			t.Errorf("expected draft-mismatch, got %v", findings)
+		}
+	})
+
+	t.Run("extension keywords", func(t *testing.T) {
+		c := jsonschema.NewCompiler()
+		c.Extensions["powerOf"] = powerOfExt()
+		if err := c.AddResource("test.json", strings.NewReader(`{"powerOf": 10}`)); err != nil {
+			t.Fatal(err)
+		}
+		findings, err := c.Lint(ctx, "test.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(findings) != 0 {
+			t.Errorf("expected no findings, got %v", findings)
+		}
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		if _, err := jsonschema.NewCompiler().Lint(ctx, "testdata/invalid_schema.json"); err == nil {
+			t.Error("error expected")
+		}
+	})
+}
@@ -0,0 +1,259 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Catalog is a set of message templates, keyed by keyword, used to render
+// the messages of validation errors in a language. Errors, which only group
+// their causes, and errors of false schemas have the empty keyword.
+//
+// Templates use the syntax of text/template. Their named parameters are the
+// fields of the error's Context, with lower-case first letter, such as
+// {{.expected}} and {{.actual}} for *ValidationErrorContextMinLength, plus
+// {{.keyword}}, {{.schemaURL}} and {{.causes}}, the number of causes. Besides
+// the builtin functions, templates can use:
+//
+//	quote   quotes a string and encodes other values as json. Applied to
+//	        each element of a list.
+//	json    encodes a value as json.
+//	join    joins the elements of a list with a separator.
+//	name    returns the name of the property, a json-pointer refers to.
+//	        Applied to each element of a list.
+//	goSyntax
+//	        formats a value as by fmt's %#v verb. Applied to each element
+//	        of a list.
+//	primitive
+//	        tells whether a value is neither object nor array.
+//	hasPrefix, trimPrefix
+//	        as in package strings.
+//
+// The message of an error is left unchanged, if the catalog has no template
+// for its keyword, or the template fails, or it is replaced by errorMessage
+// keyword.
+type Catalog map[string]string
+
+// English is the catalog of the default messages. The messages of the
+// keywords, which it has no template for, are set by their extensions.
+var English = Catalog{
+	"":                     `{{if .causes}}validation failed{{else if .property}}patternProperty {{quote .property}} is not valid regex{{else}}always fail{{end}}`,
+	"$ref":                 `doesn't validate with {{if hasPrefix .ref (print .schemaURL "#")}}{{quote (trimPrefix .ref .schemaURL)}}{{else}}{{quote .ref}}{{end}}`,
+	"type":                 `expected {{join .expected " or "}}, but got {{.actual}}`,
+	"const":                `{{if primitive .expected}}value must be {{goSyntax .expected}}{{else}}const failed{{end}}`,
+	"enum":                 `{{$primitive := true}}{{range .expected}}{{if not (primitive .)}}{{$primitive = false}}{{end}}{{end}}{{if not $primitive}}enum failed{{else if eq (len .expected) 1}}value must be {{goSyntax (index .expected 0)}}{{else}}value must be one of {{join (goSyntax .expected) ", "}}{{end}}`,
+	"format":               `{{printf "%q" .actual}} is not valid {{quote .format}}`,
+	"not":                  `not failed`,
+	"allOf":                `allOf failed`,
+	"anyOf":                `anyOf failed`,
+	"oneOf":                `{{if .matched}}valid against schemas at indexes {{index .matched 0}} and {{index .matched 1}}{{else}}oneOf failed{{end}}`,
+	"then":                 `if-then failed`,
+	"else":                 `if-else failed`,
+	"readOnly":             `read-only value not allowed in request`,
+	"writeOnly":            `write-only value not allowed in response`,
+	"minProperties":        `minimum {{.expected}} properties allowed, but found {{.actual}} properties`,
+	"maxProperties":        `maximum {{.expected}} properties allowed, but found {{.actual}} properties`,
+	"required":             `missing properties: {{join (quote (name .missing)) ", "}}`,
+	"additionalProperties": `additionalProperties {{join (quote (name .properties)) ", "}} not allowed`,
+	"dependencies":         `property {{quote (name .missing)}} is required, if {{quote .property}} property exists`,
+	"minItems":             `minimum {{.expected}} items allowed, but found {{.actual}} items`,
+	"maxItems":             `maximum {{.expected}} items allowed, but found {{.actual}} items`,
+	"uniqueItems":          `items at index {{index .indexes 0}} and {{index .indexes 1}} are equal`,
+	"additionalItems":      `only {{.expected}} items are allowed, but found {{.actual}} items`,
+	"contains":             `contains failed`,
+	"minLength":            `length must be >= {{.expected}}, but got {{.actual}}`,
+	"maxLength":            `length must be <= {{.expected}}, but got {{.actual}}`,
+	"pattern":              `does not match pattern {{quote .pattern}}`,
+	"contentEncoding":      `{{quote .actual}} is not {{.encoding}} encoded`,
+	"contentMediaType":     `value is not of mediatype {{quote .mediaType}}`,
+	"minimum":              `must be >= {{.expected}} but found {{.actual}}`,
+	"exclusiveMinimum":     `must be > {{.expected}} but found {{.actual}}`,
+	"maximum":              `must be <= {{.expected}} but found {{.actual}}`,
+	"exclusiveMaximum":     `must be < {{.expected}} but found {{.actual}}`,
+	"multipleOf":           `{{.actual}} not multipleOf {{.expected}}`,
+}
+
+// catalogs is the registry of message catalogs, keyed by language tag, used
+// by WithLanguage.
+var catalogs = struct {
+	sync.RWMutex
+	m map[string]Catalog
+}{m: map[string]Catalog{"en": English}}
+
+// RegisterCatalog registers catalog c for the language tag, such as "de" or
+// "de-CH", to be used by WithLanguage. A nil catalog removes the registered
+// one. It is safe to register catalogs while validating.
+func RegisterCatalog(tag string, c Catalog) {
+	catalogs.Lock()
+	defer catalogs.Unlock()
+	if c == nil {
+		delete(catalogs.m, tag)
+	} else {
+		catalogs.m[tag] = c
+	}
+}
+
+func lookupCatalog(tag string) Catalog {
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+	if c, ok := catalogs.m[tag]; ok {
+		return c
+	}
+	if i := strings.IndexAny(tag, "-_"); i != -1 {
+		return catalogs.m[tag[:i]]
+	}
+	return nil
+}
+
+// Localize renders the messages of the error tree rooted at err with the
+// catalog.
+func (c Catalog) Localize(err *ValidationError) {
+	if tmpl, ok := c[err.Keyword]; ok && !err.customMessage {
+		if msg, ok := renderMessage(tmpl, err); ok {
+			err.Message = msg
+		}
+	}
+	for _, cause := range err.Causes {
+		c.Localize(cause)
+	}
+}
+
+// localize renders the messages of the error tree rooted at err with catalog
+// c. The messages, which c does not render, are rendered with English, if
+// the error has no message yet.
+func localize(err *ValidationError, c Catalog) {
+	if !err.customMessage {
+		if msg, ok := c.render(err); ok {
+			err.Message = msg
+		} else if msg, ok := English.render(err); ok && err.Message == "" {
+			err.Message = msg
+		}
+	}
+	for _, cause := range err.Causes {
+		localize(cause, c)
+	}
+}
+
+func (c Catalog) render(err *ValidationError) (string, bool) {
+	tmpl, ok := c[err.Keyword]
+	if !ok {
+		return "", false
+	}
+	return renderMessage(tmpl, err)
+}
+
+var templates sync.Map // text to *template.Template, nil if invalid
+
+var messageFuncs = template.FuncMap{
+	"quote": mapList(func(v interface{}) string {
+		if s, ok := v.(string); ok {
+			return strconv.Quote(s)
+		}
+		return jsonText(v)
+	}),
+	"json": jsonText,
+	"join": func(list interface{}, sep string) string {
+		var items []string
+		eachItem(list, func(v interface{}) { items = append(items, fmt.Sprint(v)) })
+		return strings.Join(items, sep)
+	},
+	"goSyntax": mapList(func(v interface{}) string {
+		return fmt.Sprintf("%#v", v)
+	}),
+	"primitive": func(v interface{}) bool {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		}
+		return true
+	},
+	"hasPrefix":  strings.HasPrefix,
+	"trimPrefix": strings.TrimPrefix,
+	"name": mapList(func(v interface{}) string {
+		ptr := fmt.Sprint(v)
+		return unescape(ptr[strings.LastIndexByte(ptr, '/')+1:])
+	}),
+}
+
+// mapList returns function, which applies f to a value, or to each element
+// of a list.
+func mapList(f func(interface{}) string) func(interface{}) interface{} {
+	return func(v interface{}) interface{} {
+		if rv := reflect.ValueOf(v); rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return f(v)
+		}
+		var items []string
+		eachItem(v, func(v interface{}) { items = append(items, f(v)) })
+		return items
+	}
+}
+
+func eachItem(list interface{}, f func(interface{})) {
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		f(list)
+		return
+	}
+	for i := 0; i < rv.Len(); i++ {
+		f(rv.Index(i).Interface())
+	}
+}
+
+func jsonText(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func renderMessage(text string, err *ValidationError) (string, bool) {
+	t, ok := templates.Load(text)
+	if !ok {
+		parsed, perr := template.New("").Funcs(messageFuncs).Option("missingkey=zero").Parse(text)
+		if perr != nil {
+			parsed = nil
+		}
+		t, _ = templates.LoadOrStore(text, parsed)
+	}
+	tmpl := t.(*template.Template)
+	if tmpl == nil {
+		return "", false
+	}
+	var b strings.Builder
+	if tmpl.Execute(&b, messageParams(err)) != nil {
+		return "", false
+	}
+	return b.String(), true
+}
+
+// messageParams returns the named parameters of err, which are the exported
+// fields of its context.
+func messageParams(err *ValidationError) map[string]interface{} {
+	params := map[string]interface{}{
+		"keyword":   err.Keyword,
+		"schemaURL": err.SchemaURL,
+		"causes":    len(err.Causes),
+	}
+	rv := reflect.ValueOf(err.Context)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return params
+	}
+	for i := 0; i < rv.NumField(); i++ {
+		if f := rv.Type().Field(i); f.IsExported() && !f.Anonymous {
+			r, n := utf8.DecodeRuneInString(f.Name)
+			params[string(unicode.ToLower(r))+f.Name[n:]] = rv.Field(i).Interface()
+		}
+	}
+	return params
+}
@@ -0,0 +1,172 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/jsonschematest"
+)
+
+func TestEnglishCatalog(t *testing.T) {
+	// English renders the default messages
+	seen := make(map[string]bool)
+	for _, tc := range []struct {
+		schema string
+		doc    string
+		opts   []jsonschema.ValidationOption
+	}{
+		{`{"type": ["string", "null"]}`, `1`, nil},
+		{`{"const": 1.5}`, `1`, nil},
+		{`{"const": "a"}`, `1`, nil},
+		{`{"const": {"a": 1}}`, `1`, nil},
+		{`{"const": [1]}`, `1`, nil},
+		{`{"enum": ["a", true]}`, `"c"`, nil},
+		{`{"enum": [1, 2]}`, `"c"`, nil},
+		{`{"enum": ["a"]}`, `"c"`, nil},
+		{`{"enum": [1]}`, `"c"`, nil},
+		{`{"enum": ["a", {"b": 1}]}`, `"c"`, nil},
+		{`{"enum": [[1]]}`, `"c"`, nil},
+		{`{"$ref": "#/definitions/a", "definitions": {"a": {"type": "string"}}}`, `1`, nil},
+		{`{"properties": {"a": {"$ref": "#"}}, "type": "object"}`, `{"a": 1}`, nil},
+		{`{"format": "email"}`, `"alice"`, nil},
+		{`{"oneOf": [{"type": "string"}, {"minLength": 1}]}`, `"alice"`, nil},
+		{`{"oneOf": [{"type": "string"}, {"type": "boolean"}]}`, `1`, nil},
+		{`{"anyOf": [{"type": "string"}, {"minimum": 1.5}]}`, `1`, nil},
+		{`{"minProperties": 3, "maxProperties": 1}`, `{"a": 1, "b": 2}`, nil},
+		{`{"required": ["a", "b/c"], "additionalProperties": false}`, `{"x~y": 1}`, nil},
+		{`{"dependencies": {"card": ["billing"]}}`, `{"card": 1}`, nil},
+		{`{"regexProperties": true}`, `{"(": 1}`, nil},
+		{`{"uniqueItems": true, "minItems": 4, "items": [{}], "additionalItems": false}`, `[1, 1]`, nil},
+		{`{"contains": {"type": "string"}, "maxItems": 0}`, `[1]`, nil},
+		{`{"minLength": 5, "maxLength": 1, "pattern": "^[a-z]+$", "contentEncoding": "base64"}`, `"A!"`, nil},
+		{`{"contentMediaType": "application/json"}`, `"{"`, nil},
+		{`{"exclusiveMinimum": 2, "exclusiveMaximum": 1, "multipleOf": 0.5, "maximum": 0.5, "minimum": 2}`, `1.25`, nil},
+		{`{"allOf": [{"not": {}}, false], "if": true, "then": false}`, `1`, nil},
+		{`{"if": false, "else": false}`, `1`, nil},
+		{`{"readOnly": true}`, `1`, []jsonschema.ValidationOption{jsonschema.WithDirection(jsonschema.Request)}},
+		{`{"writeOnly": true}`, `1`, []jsonschema.ValidationOption{jsonschema.WithDirection(jsonschema.Response)}},
+	} {
+		schema, err := jsonschema.CompileString(ctx, "test.json", tc.schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := schema.ValidateInterface(decode(t, tc.doc), tc.opts...)
+		got := schema.ValidateInterface(decode(t, tc.doc), append(tc.opts, jsonschema.WithCatalog(jsonschema.English))...)
+		if want == nil || got == nil {
+			t.Fatalf("%s: expected %s to be invalid", tc.schema, tc.doc)
+		}
+		want.(*jsonschema.ValidationError).Walk(func(ve *jsonschema.ValidationError) bool {
+			seen[ve.Keyword] = true
+			return true
+		})
+		w := jsonschematest.Render(want.(*jsonschema.ValidationError))
+		g := jsonschematest.Render(got.(*jsonschema.ValidationError))
+		if w != g {
+			t.Errorf("%s: messages differ:\ngot:\n%s\nwant:\n%s", tc.schema, g, w)
+		}
+	}
+	for keyword := range jsonschema.English {
+		if !seen[keyword] {
+			t.Errorf("keyword %q not tested", keyword)
+		}
+	}
+}
+
+func TestWithLanguage(t *testing.T) {
+	jsonschema.RegisterCatalog("de", jsonschema.Catalog{
+		"minLength": `Länge muss mindestens {{.expected}} sein, ist aber {{.actual}}`,
+		"required":  `fehlende Eigenschaften: {{join (quote (name .missing)) ", "}}`,
+		"pattern":   `{{.invalid`,
+	})
+	defer jsonschema.RegisterCatalog("de", nil)
+
+	schema, err := jsonschema.CompileString(ctx, "test.json", `{
+		"required": ["name", "email"],
+		"properties": {"name": {"minLength": 2, "pattern": "^[a-z]+$", "format": "email"}}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for tag, want := range map[string]string{
+		"de-CH": `I[#] S[test.json#] validation failed
+  I[#] S[test.json#/required] fehlende Eigenschaften: "email"
+  I[#/name] S[test.json#/properties/name] validation failed
+    I[#/name] S[test.json#/properties/name/format] "A" is not valid "email"
+    I[#/name] S[test.json#/properties/name/minLength] Länge muss mindestens 2 sein, ist aber 1
+    I[#/name] S[test.json#/properties/name/pattern] does not match pattern "^[a-z]+$"
+`,
+		"fr": `I[#] S[test.json#] validation failed
+  I[#] S[test.json#/required] missing properties: "email"
+  I[#/name] S[test.json#/properties/name] validation failed
+    I[#/name] S[test.json#/properties/name/format] "A" is not valid "email"
+    I[#/name] S[test.json#/properties/name/minLength] length must be >= 2, but got 1
+    I[#/name] S[test.json#/properties/name/pattern] does not match pattern "^[a-z]+$"
+`,
+	} {
+		err := schema.ValidateInterface(decode(t, `{"name": "A"}`), jsonschema.WithLanguage(tag))
+		if err == nil {
+			t.Fatal("validation must fail")
+		}
+		if got := jsonschematest.Render(err.(*jsonschema.ValidationError)); got != want {
+			t.Errorf("%s:\ngot:\n%s\nwant:\n%s", tag, got, want)
+		}
+	}
+}
+
+func TestCatalogExtension(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	c.Extensions["powerOf"] = powerOfExt()
+	if err := c.AddResource("test.json", strings.NewReader(`{"powerOf": 10}`)); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := c.Compile(ctx, "test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog := jsonschema.Catalog{"powerOf": `keine Potenz von zehn ({{.keyword}})`}
+	err = schema.ValidateInterface(decode(t, `111`), jsonschema.WithCatalog(catalog))
+	if err == nil {
+		t.Fatal("validation must fail")
+	}
+	if got := err.(*jsonschema.ValidationError).Message; got != "keine Potenz von zehn (powerOf)" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func decode(t *testing.T, doc string) interface{} {
+	t.Helper()
+	v, err := jsonschema.DecodeJSON(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestRegisterCatalogConcurrent(t *testing.T) {
+	schema, err := jsonschema.CompileString(ctx, "test.json", `{"minLength": 2}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer jsonschema.RegisterCatalog("xx", nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				jsonschema.RegisterCatalog("xx", jsonschema.Catalog{"minLength": `zu kurz`})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if err := schema.ValidateInterface("a", jsonschema.WithLanguage("xx")); err == nil {
+					t.Error("validation must fail")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
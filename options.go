@@ -0,0 +1,169 @@
+package jsonschema
+
+// ValidationOption configures a single call of Schema.Validate or
+// Schema.ValidateInterface.
+type ValidationOption func(*validationOptions)
+
+type validationOptions struct {
+	catalog        Catalog
+	direction      Direction
+	stripForbidden bool
+	stripped       []property // properties to remove after validation.
+	partial        bool
+}
+
+func newValidationOptions(opts []ValidationOption) *validationOptions {
+	o := &validationOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithLanguage renders the messages of validation errors with the catalog
+// registered by RegisterCatalog for the language tag, such as "de-CH". If there is
+// none, the catalog of the base language, such as "de", is used. Messages
+// are left unchanged, if neither is registered.
+func WithLanguage(tag string) ValidationOption {
+	return func(o *validationOptions) {
+		o.catalog = lookupCatalog(tag)
+	}
+}
+
+// WithCatalog renders the messages of validation errors with catalog c.
+func WithCatalog(c Catalog) ValidationOption {
+	return func(o *validationOptions) {
+		o.catalog = c
+	}
+}
+
+// WithPartial validates a partial document, such as the payload of a PATCH
+// request, against the schema of the full document. Properties are not
+// checked for presence by "required", "minProperties" and "dependencies",
+// but all properties present are validated.
+func WithPartial() ValidationOption {
+	return func(o *validationOptions) {
+		o.partial = true
+	}
+}
+
+// checksPresence tells whether missing properties are reported.
+func (o *validationOptions) checksPresence() bool {
+	return o == nil || !o.partial
+}
+
+// Direction tells whether a document is sent to an API or returned by it,
+// for enforcing readOnly and writeOnly as in OpenAPI.
+type Direction int
+
+const (
+	// Request is the direction of documents sent to an API. Properties
+	// with "readOnly": true are not allowed, and not required.
+	Request Direction = iota + 1
+
+	// Response is the direction of documents returned by an API. Properties
+	// with "writeOnly": true are not allowed, and not required.
+	Response
+)
+
+// WithDirection enforces readOnly or writeOnly for documents sent in
+// direction d. Values not allowed are reported with keyword "readOnly" or
+// "writeOnly", unless they are stripped, see WithStrip.
+func WithDirection(d Direction) ValidationOption {
+	return func(o *validationOptions) {
+		o.direction = d
+	}
+}
+
+// WithStrip removes properties, which are not allowed in the direction
+// given by WithDirection, from the validated document instead of reporting
+// them. The document passed to Schema.ValidateInterface is modified, even if
+// it is not valid. Properties are removed, if their schema in any subschema
+// applying to the value does not allow them. Subschemas of "if" and "not",
+// and branches of "anyOf" and "oneOf" which do not match, do not apply.
+func WithStrip() ValidationOption {
+	return func(o *validationOptions) {
+		o.stripForbidden = true
+	}
+}
+
+// forbids tells whether the value of schema s is not allowed in the
+// direction. s is nil, if the value has no schema.
+func (o *validationOptions) forbids(s *Schema) bool {
+	if o == nil || s == nil {
+		return false
+	}
+	switch o.direction {
+	case Request:
+		return s.readOnly
+	case Response:
+		return s.writeOnly
+	}
+	return false
+}
+
+// omits tells whether the value of schema s, or of the schemas s refers to
+// by "$ref" and "allOf", is not allowed in the direction. The subschemas of
+// "anyOf", "oneOf" and "if" are not followed, since they apply to the value
+// only if it matches them.
+func (o *validationOptions) omits(s *Schema) bool {
+	if s == nil {
+		return false
+	}
+	if o.forbids(s) || o.omits(s.Ref) {
+		return true
+	}
+	for _, sub := range s.AllOf {
+		if o.omits(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *validationOptions) forbiddenError() *ValidationError {
+	if o.direction == Request {
+		return keywordError("readOnly").
+			withContext(&ValidationErrorContextReadOnly{})
+	}
+	return keywordError("writeOnly").
+		withContext(&ValidationErrorContextWriteOnly{})
+}
+
+// strips tells whether property pname of object v is removed, because it is
+// not allowed in the direction. The property is removed after validation.
+func (o *validationOptions) strips(v map[string]interface{}, pname string, s *Schema) bool {
+	if o == nil || !o.stripForbidden || !o.omits(s) {
+		return false
+	}
+	o.stripped = append(o.stripped, property{v, pname})
+	return true
+}
+
+// stripMark returns a mark, which unstrip takes to forget the properties
+// recorded by strips since, when they are recorded by a subschema which
+// does not apply to the value, such as "if" or a failed "anyOf" branch.
+func (o *validationOptions) stripMark() int {
+	if o == nil {
+		return 0
+	}
+	return len(o.stripped)
+}
+
+func (o *validationOptions) unstrip(mark int) {
+	if o != nil {
+		o.stripped = o.stripped[:mark]
+	}
+}
+
+// strip removes the properties recorded by strips.
+func (o *validationOptions) strip() {
+	for _, p := range o.stripped {
+		delete(p.object, p.name)
+	}
+}
+
+type property struct {
+	object map[string]interface{}
+	name   string
+}
@@ -0,0 +1,72 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+func TestPartial(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("test.json", strings.NewReader(`{
+		"required": ["email", "name"],
+		"minProperties": 2,
+		"properties": {
+			"email": {"type": "string", "format": "email"},
+			"name": {
+				"required": ["first"],
+				"properties": {"first": {"type": "string"}, "last": {"type": "string"}}
+			}
+		},
+		"dependencies": {
+			"email": ["name"],
+			"phone": {"properties": {"email": {"maxLength": 5}}}
+		}
+	}`)); err != nil {
+		t.Fatal(err)
+	}
+	s, err := c.Compile(ctx, "test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		doc      string
+		keywords []string // keywords of leaf errors, nil if valid
+	}{
+		{"empty", `{}`, nil},
+		{"nested", `{"name": {"last": "doe"}}`, nil},
+		{"dependency", `{"email": "a@b.com"}`, nil},
+		{"invalid property", `{"email": "a"}`, []string{"format"}},
+		{"invalid nested property", `{"name": {"first": 1}}`, []string{"type"}},
+		{"schema dependency", `{"email": "alice@example.com", "phone": "1"}`, []string{"maxLength"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := s.ValidateInterface(decode(t, test.doc), jsonschema.WithPartial())
+			if test.keywords == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			verr, ok := err.(*jsonschema.ValidationError)
+			if !ok {
+				t.Fatalf("got %v, want *ValidationError", err)
+			}
+			var keywords []string
+			for _, leaf := range verr.Leaves() {
+				keywords = append(keywords, leaf.Keyword)
+			}
+			if strings.Join(keywords, ",") != strings.Join(test.keywords, ",") {
+				t.Errorf("got errors of %v, want %v", keywords, test.keywords)
+			}
+		})
+	}
+
+	if err := s.ValidateInterface(decode(t, `{"email": "a@b.com"}`)); err == nil {
+		t.Error("presence not checked without WithPartial")
+	}
+}
@@ -0,0 +1,309 @@
+package patch
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/internal/jsonvalue"
+)
+
+// applyJSONPatch returns a copy of doc with ops applied, and the changes
+// made.
+func applyJSONPatch(doc interface{}, ops []Operation) (interface{}, []change, error) {
+	doc = jsonvalue.Copy(doc)
+	var changes []change
+	for i, op := range ops {
+		path, err := parsePointer(op.Path)
+		if err != nil {
+			return nil, nil, opError(i, op, err)
+		}
+		record := func(path []string) {
+			changes = append(changes, change{index: i, patchPtr: "#/" + strconv.Itoa(i), path: path})
+		}
+		switch op.Op {
+		case "add":
+			if doc, path, err = add(doc, path, jsonvalue.Copy(op.Value)); err == nil {
+				changes = rebase(doc, changes, path, 1)
+				record(path)
+			}
+		case "remove":
+			if doc, _, err = remove(doc, path); err == nil {
+				changes = rebase(doc, changes, path, -1)
+				record(path)
+			}
+		case "replace":
+			doc, err = replace(doc, path, jsonvalue.Copy(op.Value))
+			record(path)
+		case "move", "copy":
+			var from []string
+			if from, err = parsePointer(op.From); err != nil {
+				break
+			}
+			var v interface{}
+			if op.Op == "move" {
+				if len(from) < len(path) && overlaps(from, path) {
+					err = fmt.Errorf("cannot move %q into itself", op.From)
+					break
+				}
+				if doc, v, err = remove(doc, from); err == nil {
+					changes = rebase(doc, changes, from, -1)
+					record(from)
+				}
+			} else {
+				v, err = get(doc, from)
+				v = jsonvalue.Copy(v)
+			}
+			if err == nil {
+				if doc, path, err = add(doc, path, v); err == nil {
+					changes = rebase(doc, changes, path, 1)
+					record(path)
+				}
+			}
+		case "test":
+			var v interface{}
+			if v, err = get(doc, path); err == nil && !jsonvalue.Equal(v, op.Value) {
+				err = fmt.Errorf("value at %q is not equal", op.Path)
+			}
+		default:
+			err = fmt.Errorf("unknown op %q", op.Op)
+		}
+		if err != nil {
+			return nil, nil, opError(i, op, err)
+		}
+	}
+	return doc, changes, nil
+}
+
+func opError(i int, op Operation, err error) error {
+	return fmt.Errorf("json patch operation %d (%s %q): %v", i, op.Op, op.Path, err)
+}
+
+// unescaper converts json-pointer token to the token.
+var unescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// parsePointer returns the unescaped tokens of json-pointer ptr.
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("invalid json-pointer %q", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = unescaper.Replace(token)
+	}
+	return tokens, nil
+}
+
+// index returns the array index of token. end tells whether "-" and the
+// length of the array are allowed, which refer to the end of the array.
+func index(token string, n int, end bool) (int, error) {
+	if token == "-" && end {
+		return n, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || token != strconv.Itoa(i) || i > n || i == n && !end {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return i, nil
+}
+
+func get(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch v := doc.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if doc, ok = v[token]; !ok {
+				return nil, fmt.Errorf("property %q not found", token)
+			}
+		case []interface{}:
+			i, err := index(token, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = v[i]
+		default:
+			return nil, fmt.Errorf("cannot get %q of %s", token, jsonvalue.Type(v))
+		}
+	}
+	return doc, nil
+}
+
+// rebase returns changes with their paths rebased, after a value is inserted
+// (delta 1) at or removed (delta -1) from path of doc, if the value is an
+// item of an array. Changes of a removed item are dropped.
+func rebase(doc interface{}, changes []change, path []string, delta int) []change {
+	if len(path) == 0 {
+		return changes
+	}
+	parent := path[:len(path)-1]
+	if v, err := get(doc, parent); err != nil || jsonvalue.Type(v) != "array" {
+		return changes
+	}
+	i, _ := strconv.Atoi(path[len(path)-1])
+	rebased := changes[:0]
+	for _, c := range changes {
+		if len(c.path) > len(parent) && overlaps(parent, c.path) {
+			if j, err := strconv.Atoi(c.path[len(parent)]); err == nil {
+				switch {
+				case j == i && delta < 0:
+					continue
+				case j >= i:
+					c.path = append([]string(nil), c.path...)
+					c.path[len(parent)] = strconv.Itoa(j + delta)
+				}
+			}
+		}
+		rebased = append(rebased, c)
+	}
+	return rebased
+}
+
+// update returns doc, in which the container at path[:len(path)-1] is
+// replaced by the result of f.
+func update(doc interface{}, path []string, f func(container interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return f(doc, path[0])
+	}
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		child, ok := v[path[0]]
+		if !ok {
+			return nil, fmt.Errorf("property %q not found", path[0])
+		}
+		child, err := update(child, path[1:], f)
+		v[path[0]] = child
+		return v, err
+	case []interface{}:
+		i, err := index(path[0], len(v), false)
+		if err != nil {
+			return nil, err
+		}
+		child, err := update(v[i], path[1:], f)
+		v[i] = child
+		return v, err
+	}
+	return nil, fmt.Errorf("cannot get %q of %s", path[0], jsonvalue.Type(doc))
+}
+
+// add returns doc with value added at path, and path with "-" replaced by
+// the index of the value.
+func add(doc interface{}, path []string, value interface{}) (interface{}, []string, error) {
+	if len(path) == 0 {
+		return value, path, nil
+	}
+	path = append([]string(nil), path...)
+	doc, err := update(doc, path, func(container interface{}, token string) (interface{}, error) {
+		switch v := container.(type) {
+		case map[string]interface{}:
+			v[token] = value
+			return v, nil
+		case []interface{}:
+			i, err := index(token, len(v), true)
+			if err != nil {
+				return nil, err
+			}
+			path[len(path)-1] = strconv.Itoa(i)
+			v = append(v, nil)
+			copy(v[i+1:], v[i:])
+			v[i] = value
+			return v, nil
+		}
+		return nil, fmt.Errorf("cannot add %q to %s", token, jsonvalue.Type(container))
+	})
+	return doc, path, err
+}
+
+// replace returns doc with the value at path replaced by value.
+func replace(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(container interface{}, token string) (interface{}, error) {
+		switch v := container.(type) {
+		case map[string]interface{}:
+			if _, ok := v[token]; !ok {
+				return nil, fmt.Errorf("property %q not found", token)
+			}
+			v[token] = value
+			return v, nil
+		case []interface{}:
+			i, err := index(token, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = value
+			return v, nil
+		}
+		return nil, fmt.Errorf("cannot replace %q of %s", token, jsonvalue.Type(container))
+	})
+}
+
+// remove returns doc with the value at path removed, and the value.
+func remove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove document")
+	}
+	var removed interface{}
+	doc, err := update(doc, path, func(container interface{}, token string) (interface{}, error) {
+		switch v := container.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if removed, ok = v[token]; !ok {
+				return nil, fmt.Errorf("property %q not found", token)
+			}
+			delete(v, token)
+			return v, nil
+		case []interface{}:
+			i, err := index(token, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = v[i]
+			return append(v[:i], v[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("cannot remove %q of %s", token, jsonvalue.Type(container))
+	})
+	return doc, removed, err
+}
+
+// mergePatch returns target with Merge Patch patch applied. Changes made are
+// appended to changes, if not nil. path is the path of target.
+func mergePatch(target, patch interface{}, path []string, changes *[]change) interface{} {
+	p, isObj := patch.(map[string]interface{})
+	t, wasObj := target.(map[string]interface{})
+	if changes != nil && len(path) == 0 && (!isObj || !wasObj) {
+		// the document is replaced.
+		*changes = append(*changes, change{index: -1, patchPtr: jsonschema.JoinPointer(path), path: path})
+	}
+	if !isObj {
+		return jsonvalue.Copy(patch)
+	}
+	if !wasObj {
+		t = make(map[string]interface{})
+	}
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := p[k]
+		kpath := append(append([]string(nil), path...), k)
+		_, isObj := v.(map[string]interface{})
+		_, wasObj := t[k].(map[string]interface{})
+		if changes != nil && (!isObj || !wasObj) {
+			*changes = append(*changes, change{index: -1, patchPtr: jsonschema.JoinPointer(kpath), path: kpath})
+		}
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergePatch(t[k], v, kpath, changes)
+		}
+	}
+	return t
+}
@@ -0,0 +1,200 @@
+// Package patch validates JSON Patch (https://www.rfc-editor.org/rfc/rfc6902)
+// and JSON Merge Patch (https://www.rfc-editor.org/rfc/rfc7386) documents
+// against the schema of the document they are applied to.
+//
+//	ops, err := patch.DecodeJSONPatch(r.Body)
+//	if err != nil {
+//		return err
+//	}
+//	doc, err = patch.ValidateJSONPatch(schema, doc, ops)
+//	var perr *patch.Error
+//	if errors.As(err, &perr) {
+//		for _, e := range perr.Operations {
+//			fmt.Printf("operation %d: %s\n", e.Index, e.Err.Message)
+//		}
+//	}
+//
+// The patch is applied to a copy of the document, and the result is validated.
+// Each error of the result is mapped back to the change of the patch, which
+// introduced it.
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/internal/jsonvalue"
+)
+
+// Operation is an operation of a JSON Patch.
+type Operation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value"`
+}
+
+// DecodeJSONPatch decodes the JSON Patch from r. Values are decoded as by
+// jsonschema.DecodeJSON. Operations lacking members required by RFC 6902,
+// such as "path", are rejected.
+func DecodeJSONPatch(r io.Reader) ([]Operation, error) {
+	doc, err := jsonschema.DecodeJSON(r)
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := doc.([]interface{})
+	if !ok {
+		return nil, errors.New("invalid json patch: must be array")
+	}
+	ops := make([]Operation, len(arr))
+	for i, item := range arr {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid json patch: operation %d must be object", i)
+		}
+		op := &ops[i]
+		for _, f := range []struct {
+			name string
+			dst  *string
+		}{{"op", &op.Op}, {"path", &op.Path}, {"from", &op.From}} {
+			v, ok := m[f.name]
+			if !ok {
+				if f.name != "from" || op.Op == "move" || op.Op == "copy" {
+					return nil, fmt.Errorf("invalid json patch: operation %d has no %s", i, f.name)
+				}
+				continue
+			}
+			if *f.dst, ok = v.(string); !ok {
+				return nil, fmt.Errorf("invalid json patch: %q of operation %d must be string", f.name, i)
+			}
+		}
+		op.Value = m["value"]
+		if _, ok := m["value"]; !ok && (op.Op == "add" || op.Op == "replace" || op.Op == "test") {
+			return nil, fmt.Errorf("invalid json patch: operation %d has no value", i)
+		}
+	}
+	return ops, nil
+}
+
+// Error is returned, if the patched document is not valid.
+type Error struct {
+	// Err is the validation error of the patched document.
+	Err *jsonschema.ValidationError
+
+	// Operations lists the leaf errors of the pruned error tree, see
+	// jsonschema.ValidationError.Pruned, with the changes of the patch which
+	// introduced them.
+	Operations []OperationError
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// OperationError is a validation error of the patched document, mapped to
+// the change of the patch, which introduced it. That is the last change of
+// the invalid value, of a value containing it or of a value within it.
+type OperationError struct {
+	// Index is the index of the operation of the JSON Patch. It is -1 for
+	// Merge Patches, or if the error is not introduced by the patch.
+	Index int
+
+	// PatchPtr is the json-pointer to the change in the patch document, in
+	// URI fragment form, such as "#/2" for the third operation of a JSON Patch
+	// or "#/address/street" for a member of a Merge Patch. It is "#", if a
+	// Merge Patch replaces the whole document, and empty, if the error is not
+	// introduced by the patch.
+	PatchPtr string
+
+	// Err is the leaf validation error.
+	Err *jsonschema.ValidationError
+}
+
+// change is a change of a document, made by a patch.
+type change struct {
+	index    int
+	patchPtr string
+	path     []string // tokens of the changed value, unescaped.
+}
+
+// ValidateJSONPatch applies ops to doc and validates the result against s.
+// It returns the patched document. doc is not modified.
+//
+// If the patched document is not valid, the error is *Error. Operations,
+// which cannot be applied, are reported as other errors.
+func ValidateJSONPatch(s *jsonschema.Schema, doc interface{}, ops []Operation, opts ...jsonschema.ValidationOption) (interface{}, error) {
+	doc, changes, err := applyJSONPatch(doc, ops)
+	if err != nil {
+		return nil, err
+	}
+	return doc, validate(s, doc, changes, opts)
+}
+
+// ValidateMergePatch applies Merge Patch patch to doc and validates the
+// result against s. It returns the patched document. doc is not modified.
+//
+// If the patched document is not valid, the error is *Error.
+func ValidateMergePatch(s *jsonschema.Schema, doc, patch interface{}, opts ...jsonschema.ValidationOption) (interface{}, error) {
+	var changes []change
+	doc = mergePatch(jsonvalue.Copy(doc), patch, nil, &changes)
+	return doc, validate(s, doc, changes, opts)
+}
+
+// ApplyJSONPatch returns doc with ops applied. doc is not modified.
+func ApplyJSONPatch(doc interface{}, ops []Operation) (interface{}, error) {
+	doc, _, err := applyJSONPatch(doc, ops)
+	return doc, err
+}
+
+// ApplyMergePatch returns doc with Merge Patch patch applied. doc is not
+// modified.
+func ApplyMergePatch(doc, patch interface{}) interface{} {
+	return mergePatch(jsonvalue.Copy(doc), patch, nil, nil)
+}
+
+func validate(s *jsonschema.Schema, doc interface{}, changes []change, opts []jsonschema.ValidationOption) error {
+	err := s.ValidateInterface(doc, opts...)
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+	perr := &Error{Err: verr}
+	for _, leaf := range verr.Pruned().Leaves() {
+		e := OperationError{Index: -1, Err: leaf}
+		last := -1
+		for _, ptr := range leaf.Pointers() {
+			path := jsonschema.SplitPointer(ptr)
+			for i := len(changes) - 1; i > last; i-- {
+				if overlaps(changes[i].path, path) {
+					last = i
+					break
+				}
+			}
+		}
+		if last != -1 {
+			e.Index, e.PatchPtr = changes[last].index, changes[last].patchPtr
+		}
+		perr.Operations = append(perr.Operations, e)
+	}
+	return perr
+}
+
+// overlaps tells whether the value at path p1 contains the value at path p2,
+// or the other way round.
+func overlaps(p1, p2 []string) bool {
+	if len(p1) > len(p2) {
+		p1, p2 = p2, p1
+	}
+	for i, token := range p1 {
+		if p2[i] != token {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,208 @@
+package patch_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/patch"
+)
+
+func compile(t *testing.T, schema string) *jsonschema.Schema {
+	s, err := jsonschema.CompileString(context.Background(), "test.json", schema)
+	require.NoError(t, err)
+	return s
+}
+
+func decode(t *testing.T, doc string) interface{} {
+	v, err := jsonschema.DecodeJSON(strings.NewReader(doc))
+	require.NoError(t, err)
+	return v
+}
+
+type mapped struct {
+	index       int
+	patchPtr    string
+	instancePtr string
+	keyword     string
+}
+
+func operations(t *testing.T, err error) []mapped {
+	var perr *patch.Error
+	require.True(t, errors.As(err, &perr), "got %v", err)
+	var ops []mapped
+	for _, e := range perr.Operations {
+		ops = append(ops, mapped{e.Index, e.PatchPtr, e.Err.InstancePtr, e.Err.Keyword})
+	}
+	return ops
+}
+
+func TestValidateJSONPatch(t *testing.T) {
+	s := compile(t, `{
+		"required": ["email"],
+		"additionalProperties": false,
+		"properties": {
+			"name": {},
+			"email": {},
+			"tags": {"items": {"type": "string"}},
+			"address": {"properties": {"a/b": {"type": "string"}}}
+		}
+	}`)
+	const user = `{"name": "alice", "email": "alice@example.com", "tags": ["a"], "address": {"street": "main"}}`
+	doc := decode(t, user)
+
+	ops, err := patch.DecodeJSONPatch(strings.NewReader(`[
+		{"op": "test", "path": "/name", "value": "alice"},
+		{"op": "replace", "path": "/name", "value": "bob"},
+		{"op": "add", "path": "/tags/-", "value": 1},
+		{"op": "remove", "path": "/email"},
+		{"op": "add", "path": "/address/a~1b", "value": true},
+		{"op": "copy", "from": "/address", "path": "/home"}
+	]`))
+	require.NoError(t, err)
+
+	result, err := patch.ValidateJSONPatch(s, doc, ops)
+	assert.Equal(t, []mapped{
+		{3, "#/3", "#", "required"},
+		{4, "#/4", "#/address/a~1b", "type"},
+		{2, "#/2", "#/tags/1", "type"},
+		{5, "#/5", "#", "additionalProperties"},
+	}, operations(t, err))
+	assert.Equal(t, decode(t, `{
+		"name": "bob", "tags": ["a", 1],
+		"address": {"street": "main", "a/b": true},
+		"home": {"street": "main", "a/b": true}
+	}`), result)
+	assert.Equal(t, decode(t, user), doc, "document must not be modified")
+
+	_, err = patch.ValidateJSONPatch(s, doc, []patch.Operation{{Op: "replace", Path: "/name", Value: "bob"}})
+	assert.NoError(t, err)
+}
+
+func TestValidateJSONPatchExistingError(t *testing.T) {
+	s := compile(t, `{"properties": {"name": {"minLength": 2}}}`)
+	doc := decode(t, `{"name": "a"}`)
+	_, err := patch.ValidateJSONPatch(s, doc, []patch.Operation{{Op: "add", Path: "/tags", Value: []interface{}{}}})
+	assert.Equal(t, []mapped{{-1, "", "#/name", "minLength"}}, operations(t, err))
+}
+
+func TestValidateJSONPatchShiftedArray(t *testing.T) {
+	s := compile(t, `{"properties": {"tags": {"items": {"type": "string"}}}}`)
+	doc := decode(t, `{"tags": ["a", "b", "c", "d"]}`)
+	for _, test := range []struct {
+		name, patch, ptr string
+	}{
+		{"add", `[{"op": "replace", "path": "/tags/2", "value": 1}, {"op": "add", "path": "/tags/0", "value": "x"}]`, "#/tags/3"},
+		{"remove", `[{"op": "replace", "path": "/tags/2", "value": 1}, {"op": "remove", "path": "/tags/0"}]`, "#/tags/1"},
+		{"move", `[{"op": "replace", "path": "/tags/2", "value": 1}, {"op": "move", "from": "/tags/0", "path": "/tags/3"}]`, "#/tags/1"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ops, err := patch.DecodeJSONPatch(strings.NewReader(test.patch))
+			require.NoError(t, err)
+			_, err = patch.ValidateJSONPatch(s, doc, ops)
+			assert.Equal(t, []mapped{{0, "#/0", test.ptr, "type"}}, operations(t, err))
+		})
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	for _, test := range []struct {
+		name, doc, patch, want string
+	}{
+		{"add to array", `{"a": [1, 3]}`, `[{"op": "add", "path": "/a/1", "value": 2}]`, `{"a": [1, 2, 3]}`},
+		{"add to end", `{"a": [1]}`, `[{"op": "add", "path": "/a/1", "value": 2}]`, `{"a": [1, 2]}`},
+		{"remove from array", `{"a": [1, 2, 3]}`, `[{"op": "remove", "path": "/a/0"}]`, `{"a": [2, 3]}`},
+		{"replace document", `{"a": 1}`, `[{"op": "replace", "path": "", "value": [1]}]`, `[1]`},
+		{"move", `{"a": {"b": 1}}`, `[{"op": "move", "from": "/a/b", "path": "/c"}]`, `{"a": {}, "c": 1}`},
+		{"escaped", `{}`, `[{"op": "add", "path": "/~01~1", "value": 1}]`, `{"~1/": 1}`},
+		{"test number", `{"a": 1.0}`, `[{"op": "test", "path": "/a", "value": 1}]`, `{"a": 1.0}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ops, err := patch.DecodeJSONPatch(strings.NewReader(test.patch))
+			require.NoError(t, err)
+			got, err := patch.ApplyJSONPatch(decode(t, test.doc), ops)
+			require.NoError(t, err)
+			assert.Equal(t, decode(t, test.want), got)
+		})
+	}
+}
+
+func TestApplyJSONPatchError(t *testing.T) {
+	for _, test := range []struct {
+		name, doc, patch, err string
+	}{
+		{"missing", `{}`, `[{"op": "remove", "path": "/a"}]`, `json patch operation 0 (remove "/a"): property "a" not found`},
+		{"replace missing", `{}`, `[{"op": "replace", "path": "/a", "value": 1}]`, `json patch operation 0 (replace "/a"): property "a" not found`},
+		{"index", `[1]`, `[{"op": "add", "path": "/01", "value": 1}]`, `json patch operation 0 (add "/01"): invalid array index "01"`},
+		{"test", `{"a": 1}`, `[{"op": "test", "path": "/a", "value": "1"}]`, `json patch operation 0 (test "/a"): value at "/a" is not equal`},
+		{"move into itself", `{"a": {}}`, `[{"op": "move", "from": "/a", "path": "/a/b"}]`, `json patch operation 0 (move "/a/b"): cannot move "/a" into itself`},
+		{"unknown", `{}`, `[{"op": "merge", "path": ""}]`, `json patch operation 0 (merge ""): unknown op "merge"`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ops, err := patch.DecodeJSONPatch(strings.NewReader(test.patch))
+			require.NoError(t, err)
+			_, err = patch.ApplyJSONPatch(decode(t, test.doc), ops)
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestDecodeJSONPatchError(t *testing.T) {
+	for _, test := range []struct {
+		name, patch, err string
+	}{
+		{"no value", `[{"op": "add", "path": "/a"}]`, "invalid json patch: operation 0 has no value"},
+		{"no op", `[{"path": "/a", "value": 1}]`, "invalid json patch: operation 0 has no op"},
+		{"no path", `[{"op": "remove"}]`, "invalid json patch: operation 0 has no path"},
+		{"move without from", `[{"op": "test", "path": "", "value": 1}, {"op": "move", "path": "/a"}]`, "invalid json patch: operation 1 has no from"},
+		{"copy without from", `[{"op": "copy", "path": "/a"}]`, "invalid json patch: operation 0 has no from"},
+		{"path not string", `[{"op": "add", "path": 1, "value": 1}]`, `invalid json patch: "path" of operation 0 must be string`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := patch.DecodeJSONPatch(strings.NewReader(test.patch))
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestValidateMergePatch(t *testing.T) {
+	s := compile(t, `{
+		"type": "object",
+		"required": ["email"],
+		"properties": {
+			"tags": {"items": {"type": "string"}},
+			"address": {"properties": {"street": {"type": "string"}}}
+		}
+	}`)
+	const user = `{"name": "alice", "email": "alice@example.com", "tags": ["a"], "address": {"street": "main"}}`
+	doc := decode(t, user)
+
+	result, err := patch.ValidateMergePatch(s, doc, decode(t, `{
+		"email": null,
+		"tags": ["a", 1],
+		"address": {"street": 1, "zip": "123"}
+	}`))
+	assert.Equal(t, []mapped{
+		{-1, "#/email", "#", "required"},
+		{-1, "#/address/street", "#/address/street", "type"},
+		{-1, "#/tags", "#/tags/1", "type"},
+	}, operations(t, err))
+	assert.Equal(t, decode(t, `{
+		"name": "alice", "tags": ["a", 1],
+		"address": {"street": 1, "zip": "123"}
+	}`), result)
+	assert.Equal(t, decode(t, user), doc, "document must not be modified")
+
+	_, err = patch.ValidateMergePatch(s, doc, decode(t, `[]`))
+	assert.Equal(t, []mapped{{-1, "#", "#", "type"}}, operations(t, err))
+
+	assert.Equal(t, decode(t, `{"a": {"b": 1}, "c": [1]}`), patch.ApplyMergePatch(
+		decode(t, `{"a": "x", "c": {"d": 1}}`),
+		decode(t, `{"a": {"b": 1, "e": null}, "c": [1]}`),
+	))
+}
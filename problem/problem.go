@@ -0,0 +1,105 @@
+// Package problem converts validation errors into RFC 9457 problem details
+// (https://www.rfc-editor.org/rfc/rfc9457) and into messages per form field.
+//
+//	if err := schema.Validate(r.Body); err != nil {
+//		var verr *jsonschema.ValidationError
+//		if errors.As(err, &verr) {
+//			problem.New(verr).Write(w)
+//			return
+//		}
+//	}
+//
+// Both list the leaf errors of the pruned error tree, see
+// jsonschema.ValidationError.Pruned. Errors about properties of an object,
+// such as "required" and "additionalProperties", are reported once for each
+// property, at the pointer of the property.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+// ContentType is the media type of problem details.
+const ContentType = "application/problem+json"
+
+// Details is a problem details document, with extension member "errors".
+type Details struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Errors lists the validation errors.
+	Errors []Error `json:"errors,omitempty"`
+}
+
+// Error is a validation error in Details.
+type Error struct {
+	// Pointer is the json-pointer to the invalid value, in URI fragment
+	// form, such as "#/address/street".
+	Pointer string `json:"pointer"`
+
+	// Keyword is the failed keyword, such as "minLength".
+	Keyword string `json:"keyword,omitempty"`
+
+	// Detail is the message of the error.
+	Detail string `json:"detail"`
+}
+
+// New returns problem details with status 422 for err. Detail is the message
+// of err's best match. Type and Instance can be set by the caller.
+func New(err *jsonschema.ValidationError) *Details {
+	d := &Details{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusUnprocessableEntity),
+		Status: http.StatusUnprocessableEntity,
+		Detail: err.BestMatch().Message,
+	}
+	for _, leaf := range err.Pruned().Leaves() {
+		for _, ptr := range leaf.Pointers() {
+			d.Errors = append(d.Errors, Error{Pointer: ptr, Keyword: leaf.Keyword, Detail: leaf.Message})
+		}
+	}
+	return d
+}
+
+// Write writes d as response to w, with its status and ContentType.
+func (d *Details) Write(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", ContentType)
+	status := d.Status
+	if status == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(d)
+}
+
+// Fields returns the messages of err, keyed by the field path of the
+// invalid value, as returned by FieldPath.
+func Fields(err *jsonschema.ValidationError) map[string][]string {
+	return FieldsFunc(err, FieldPath)
+}
+
+// FieldsFunc is like Fields, but converts the json-pointers of the invalid
+// values to field paths with function path.
+func FieldsFunc(err *jsonschema.ValidationError, path func(pointer string) string) map[string][]string {
+	fields := make(map[string][]string)
+	for _, leaf := range err.Pruned().Leaves() {
+		for _, ptr := range leaf.Pointers() {
+			field := path(ptr)
+			fields[field] = append(fields[field], leaf.Message)
+		}
+	}
+	return fields
+}
+
+// FieldPath converts json-pointer "#/address/lines/0" to field path
+// "address.lines.0". The pointer to the document is converted to "".
+func FieldPath(pointer string) string {
+	return strings.Join(jsonschema.SplitPointer(pointer), ".")
+}
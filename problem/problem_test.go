@@ -0,0 +1,99 @@
+package problem_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/problem"
+)
+
+func validate(t *testing.T, doc string) *jsonschema.ValidationError {
+	schema, err := jsonschema.CompileString(context.Background(), "user.json", `{
+		"type": "object",
+		"required": ["name", "email"],
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "minLength": 2},
+			"email": {"type": "string"},
+			"address": {
+				"properties": {
+					"lines": {"items": {"type": "string"}},
+					"a/b": {"type": "string"}
+				}
+			},
+			"contact": {"anyOf": [{"type": "string", "format": "email"}, {"type": "integer"}]}
+		}
+	}`)
+	require.NoError(t, err)
+	err = schema.Validate(strings.NewReader(doc))
+	require.Error(t, err)
+	return err.(*jsonschema.ValidationError)
+}
+
+const doc = `{"name": "A", "address": {"lines": ["x", 1], "a/b": 1}, "contact": "alice", "age": 3}`
+
+func TestNew(t *testing.T) {
+	d := problem.New(validate(t, doc))
+	assert.Equal(t, "about:blank", d.Type)
+	assert.Equal(t, http.StatusUnprocessableEntity, d.Status)
+	assert.Equal(t, "Unprocessable Entity", d.Title)
+	assert.Equal(t, []problem.Error{
+		{Pointer: "#/email", Keyword: "required", Detail: `missing properties: "email"`},
+		{Pointer: "#/address/a~1b", Keyword: "type", Detail: "expected string, but got number"},
+		{Pointer: "#/address/lines/1", Keyword: "type", Detail: "expected string, but got number"},
+		{Pointer: "#/contact", Keyword: "format", Detail: `"alice" is not valid "email"`},
+		{Pointer: "#/name", Keyword: "minLength", Detail: "length must be >= 2, but got 1"},
+		{Pointer: "#/age", Keyword: "additionalProperties", Detail: `additionalProperties "age" not allowed`},
+	}, d.Errors)
+}
+
+func TestWrite(t *testing.T) {
+	d := problem.New(validate(t, `{"name": "Alice"}`))
+	d.Instance = "/users/1"
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, d.Write(rec))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Unprocessable Entity",
+		"status": 422,
+		"detail": "missing properties: \"email\"",
+		"instance": "/users/1",
+		"errors": [{"pointer": "#/email", "keyword": "required", "detail": "missing properties: \"email\""}]
+	}`, rec.Body.String())
+}
+
+func TestFields(t *testing.T) {
+	assert.Equal(t, map[string][]string{
+		"email":           {`missing properties: "email"`},
+		"address.a/b":     {"expected string, but got number"},
+		"address.lines.1": {"expected string, but got number"},
+		"contact":         {`"alice" is not valid "email"`},
+		"name":            {"length must be >= 2, but got 1"},
+		"age":             {`additionalProperties "age" not allowed`},
+	}, problem.Fields(validate(t, doc)))
+
+	fields := problem.FieldsFunc(validate(t, `[]`), func(pointer string) string { return "user" + pointer })
+	assert.Equal(t, map[string][]string{"user#": {"expected object, but got array"}}, fields)
+}
+
+func TestFieldPath(t *testing.T) {
+	for pointer, path := range map[string]string{
+		"#":                 "",
+		"#/name":            "name",
+		"#/address/lines/0": "address.lines.0",
+		"#/a~1b/c~0d":       "a/b.c~d",
+		"#/first%20name":    "first name",
+	} {
+		assert.Equal(t, path, problem.FieldPath(pointer), pointer)
+	}
+}
@@ -0,0 +1,175 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/jsonschematest"
+)
+
+func TestDirection(t *testing.T) {
+	s, err := jsonschema.CompileString(ctx, "test.json", `{
+		"required": ["id", "name", "password"],
+		"properties": {
+			"id": {"type": "string", "readOnly": true},
+			"name": {"type": "string"},
+			"password": {"$ref": "#/definitions/password"}
+		},
+		"patternProperties": {
+			"^created": {"readOnly": true}
+		},
+		"definitions": {
+			"password": {"type": "string", "writeOnly": true}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		doc       string
+		direction jsonschema.Direction
+		ptrs      []string // instance pointers of leaf errors
+	}{
+		{"request", `{"name": "a", "password": "p"}`, jsonschema.Request, nil},
+		{"request with readOnly", `{"id": "1", "name": "a", "password": "p", "createdAt": 1}`, jsonschema.Request, []string{"#/id", "#/createdAt"}},
+		{"response", `{"id": "1", "name": "a", "createdAt": 1}`, jsonschema.Response, nil},
+		{"response with writeOnly", `{"id": "1", "name": "a", "password": "p"}`, jsonschema.Response, []string{"#/password"}},
+		{"no direction", `{"name": "a"}`, 0, []string{"#"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := s.ValidateInterface(decode(t, test.doc), jsonschema.WithDirection(test.direction))
+			if test.ptrs == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			verr, ok := err.(*jsonschema.ValidationError)
+			if !ok {
+				t.Fatalf("got %v, want *ValidationError", err)
+			}
+			var ptrs []string
+			for _, leaf := range verr.Leaves() {
+				ptrs = append(ptrs, leaf.InstancePtr)
+			}
+			if !reflect.DeepEqual(ptrs, test.ptrs) {
+				t.Errorf("got errors at %v, want %v", ptrs, test.ptrs)
+			}
+		})
+	}
+
+	jsonschematest.AssertValid(t, s, `{"id": "1", "name": "a", "password": "p"}`)
+}
+
+func TestDirectionError(t *testing.T) {
+	s, err := jsonschema.CompileString(ctx, "test.json", `{
+		"properties": {"password": {"$ref": "#/definitions/password"}},
+		"definitions": {"password": {"type": "string", "writeOnly": true}}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.ValidateInterface(decode(t, `{"password": "p"}`), jsonschema.WithDirection(jsonschema.Response))
+	verr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		t.Fatalf("got %v, want *ValidationError", err)
+	}
+	leaf := verr.BestMatch()
+	if leaf.Keyword != "writeOnly" || !strings.HasSuffix(leaf.SchemaURL+leaf.SchemaPtr, "#/definitions/password/writeOnly") || leaf.InstancePtr != "#/password" {
+		t.Errorf("got %s%s keyword %q at %s", leaf.SchemaURL, leaf.SchemaPtr, leaf.Keyword, leaf.InstancePtr)
+	}
+	if _, ok := leaf.Context.(*jsonschema.ValidationErrorContextWriteOnly); !ok {
+		t.Errorf("got context %T", leaf.Context)
+	}
+	if want := "write-only value not allowed in response"; leaf.Message != want {
+		t.Errorf("got message %q, want %q", leaf.Message, want)
+	}
+}
+
+func TestStrip(t *testing.T) {
+	s, err := jsonschema.CompileString(ctx, "test.json", `{
+		"required": ["id", "name", "password"],
+		"properties": {
+			"id": {"readOnly": true},
+			"password": {"$ref": "#/definitions/password"}
+		},
+		"patternProperties": {"^created": {"readOnly": true}},
+		"definitions": {"password": {"writeOnly": true}}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		direction jsonschema.Direction
+		doc, want string
+	}{
+		{jsonschema.Request, `{"id": "1", "name": "a", "password": "p", "createdAt": 1}`, `{"name": "a", "password": "p"}`},
+		{jsonschema.Response, `{"id": "1", "name": "a", "password": "p"}`, `{"id": "1", "name": "a"}`},
+	} {
+		doc := decode(t, tc.doc)
+		if err := s.ValidateInterface(doc, jsonschema.WithDirection(tc.direction), jsonschema.WithStrip()); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.doc, err)
+		}
+		if want := decode(t, tc.want); !reflect.DeepEqual(doc, want) {
+			t.Errorf("%s: got %v, want %v", tc.doc, doc, want)
+		}
+	}
+}
+
+func TestStripApplicableSubschemas(t *testing.T) {
+	s, err := jsonschema.CompileString(ctx, "test.json", `{
+		"if": {"properties": {"id": {"readOnly": true}}},
+		"then": {"properties": {"kind": {"const": "user"}}},
+		"anyOf": [
+			{"properties": {"kind": {"const": "group"}, "owner": {"readOnly": true}}},
+			{"properties": {"kind": {"const": "user"}, "created": {"readOnly": true}}}
+		]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// id is read-only only in "if", and owner only in the failed anyOf branch.
+	doc := decode(t, `{"kind": "user", "id": "1", "owner": "alice", "created": 1}`)
+	if err := s.ValidateInterface(doc, jsonschema.WithDirection(jsonschema.Request), jsonschema.WithStrip()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := decode(t, `{"kind": "user", "id": "1", "owner": "alice"}`); !reflect.DeepEqual(doc, want) {
+		t.Errorf("got %v, want %v", doc, want)
+	}
+}
+
+func TestDirectionAllOf(t *testing.T) {
+	s, err := jsonschema.CompileString(ctx, "test.json", `{
+		"required": ["id", "name"],
+		"properties": {
+			"id": {"allOf": [{"type": "string"}, {"readOnly": true}]},
+			"name": {"type": "string"}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// id is read-only by allOf, so it is not required in a request.
+	if err := s.ValidateInterface(decode(t, `{"name": "a"}`), jsonschema.WithDirection(jsonschema.Request)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.ValidateInterface(decode(t, `{"name": "a"}`), jsonschema.WithDirection(jsonschema.Response)); err == nil {
+		t.Fatal("want error for missing id in response")
+	}
+
+	doc := decode(t, `{"id": "1", "name": "a"}`)
+	if err := s.ValidateInterface(doc, jsonschema.WithDirection(jsonschema.Request), jsonschema.WithStrip()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := decode(t, `{"name": "a"}`); !reflect.DeepEqual(doc, want) {
+		t.Errorf("got %v, want %v", doc, want)
+	}
+}
@@ -0,0 +1,101 @@
+package jsonschema
+
+import "strings"
+
+// DefaultMask replaces redacted values, if Redaction.Mask is empty.
+const DefaultMask = "***"
+
+// Redaction tells which values of a document are masked in the messages and
+// contexts of validation errors, so that secrets like passwords do not leak
+// into logs. It is set by Compiler.Redaction.
+//
+// Values are masked in the Actual field of the error contexts, and in the
+// messages of keywords embedding them, such as "format" and "minimum", which
+// are rendered with the catalog of the validation. Messages of other
+// keywords, such as "minLength", are kept. Messages of extensions are
+// replaced, as they may embed the value. For the errors of "propertyNames",
+// the property name is masked in InstancePtr too.
+type Redaction struct {
+	// WriteOnly masks the values of schemas with "writeOnly": true.
+	WriteOnly bool
+
+	// Keyword masks the values of schemas, which have this custom annotation
+	// with value true, such as "x-sensitive".
+	Keyword string
+
+	// Pointer masks the values, for whose json-pointer, such as
+	// "#/credentials/password", it returns true. It is called with the
+	// InstancePtr of each error.
+	Pointer func(instancePtr string) bool
+
+	// Mask replaces the values. If empty, DefaultMask is used.
+	Mask string
+}
+
+// sensitive tells whether the values of schema m are masked.
+func (r *Redaction) sensitive(m map[string]interface{}) bool {
+	if r.WriteOnly && m["writeOnly"] == true {
+		return true
+	}
+	return r.Keyword != "" && m[r.Keyword] == true
+}
+
+// markSensitive marks the errors of the tree rooted at ve to be redacted.
+func (ve *ValidationError) markSensitive() {
+	ve.Walk(func(err *ValidationError) bool {
+		err.sensitive = true
+		return true
+	})
+}
+
+// redact masks the values in the error tree rooted at ve, which are marked
+// sensitive or match r.Pointer.
+func (r *Redaction) redact(ve *ValidationError) {
+	mask := r.Mask
+	if mask == "" {
+		mask = DefaultMask
+	}
+	ve.Walk(func(err *ValidationError) bool {
+		if err.sensitive || r.Pointer != nil && r.Pointer(err.InstancePtr) {
+			err.redact(mask)
+		}
+		return true
+	})
+}
+
+func (ve *ValidationError) redact(mask string) {
+	switch c := ve.Context.(type) {
+	case *ValidationErrorContextConst:
+		c.Actual = mask
+	case *ValidationErrorContextEnum:
+		c.Actual = mask
+	case *ValidationErrorContextFormat:
+		c.Actual = mask
+	case *ValidationErrorContextPattern:
+		c.Actual = mask
+	case *ValidationErrorContextContentEncoding:
+		c.Actual = mask
+	case *ValidationErrorContextMinimum:
+		c.Actual = mask
+	case *ValidationErrorContextExclusiveMinimum:
+		c.Actual = mask
+	case *ValidationErrorContextMaximum:
+		c.Actual = mask
+	case *ValidationErrorContextExclusiveMaximum:
+		c.Actual = mask
+	case *ValidationErrorContextMultipleOf:
+		c.Actual = mask
+	}
+	if ve.propertyName {
+		// the value is the property name, which the pointer ends with.
+		ve.InstancePtr = ve.InstancePtr[:strings.LastIndexByte(ve.InstancePtr, '/')+1] + escape(mask)
+	}
+	if ve.customMessage {
+		return
+	}
+	// messages of keywords are rendered from the masked context, when the
+	// error is reported.
+	if _, ok := English[ve.Keyword]; !ok {
+		ve.Message = ve.Keyword + " failed"
+	}
+}
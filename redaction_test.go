@@ -0,0 +1,152 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/jsonschematest"
+)
+
+const sensitiveSchema = `{
+	"properties": {
+		"password": {"writeOnly": true, "format": "email", "minLength": 20},
+		"pin": {"x-sensitive": true, "maximum": 9999, "powerOf": 10},
+		"token": {"$ref": "#/definitions/token"},
+		"code": {"contentEncoding": "base64", "enum": ["a"]},
+		"age": {"minimum": 0}
+	},
+	"definitions": {
+		"token": {"writeOnly": true, "pattern": "^[a-z]+$"}
+	}
+}`
+
+const sensitiveDoc = `{"password": "hunter2", "pin": 12345, "token": "S3cret", "code": "!secret!", "age": -1}`
+
+func TestRedaction(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	c.Redaction = &jsonschema.Redaction{
+		WriteOnly: true,
+		Keyword:   "x-sensitive",
+		Pointer:   func(ptr string) bool { return ptr == "#/code" },
+	}
+	c.Extensions["powerOf"] = powerOfExt()
+	if err := c.AddResource("test.json", strings.NewReader(sensitiveSchema)); err != nil {
+		t.Fatal(err)
+	}
+	s, err := c.Compile(ctx, "test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verr := jsonschematest.AssertInvalid(t, s, sensitiveDoc)
+	if verr == nil {
+		t.FailNow()
+	}
+	want := `I[#] S[test.json#] validation failed
+  I[#/age] S[test.json#/properties/age/minimum] must be >= 0 but found -1
+  I[#/code] S[test.json#/properties/code] validation failed
+    I[#/code] S[test.json#/properties/code/contentEncoding] "***" is not base64 encoded
+    I[#/code] S[test.json#/properties/code/enum] value must be "a"
+  I[#/password] S[test.json#/properties/password] validation failed
+    I[#/password] S[test.json#/properties/password/format] "***" is not valid "email"
+    I[#/password] S[test.json#/properties/password/minLength] length must be >= 20, but got 7
+  I[#/pin] S[test.json#/properties/pin] validation failed
+    I[#/pin] S[test.json#/properties/pin/maximum] must be <= 9999 but found ***
+    I[#/pin] S[test.json#/properties/pin/powerOf] powerOf failed
+  I[#/token] S[test.json#/properties/token/$ref] doesn't validate with "#/definitions/token"
+    I[#/token] S[test.json#/definitions/token/pattern] does not match pattern "^[a-z]+$"
+`
+	got := jsonschematest.Render(verr)
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	for _, secret := range []string{"hunter2", "12345", "S3cret", "secret"} {
+		if strings.Contains(verr.Error(), secret) {
+			t.Errorf("%q leaked", secret)
+		}
+	}
+
+	pc := verr.ByKeyword("pattern")[0].Context.(*jsonschema.ValidationErrorContextPattern)
+	if pc.Actual != jsonschema.DefaultMask {
+		t.Errorf("expected masked context, got %q", pc.Actual)
+	}
+}
+
+func TestRedactionMessages(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		redaction *jsonschema.Redaction
+		opts      []jsonschema.ValidationOption
+		keyword   string
+		want      string
+	}{
+		{
+			name:      "mask",
+			redaction: &jsonschema.Redaction{WriteOnly: true, Mask: "<redacted>"},
+			keyword:   "format",
+			want:      `"<redacted>" is not valid "email"`,
+		},
+		{
+			// only writeOnly values are redacted
+			name:      "not sensitive",
+			redaction: &jsonschema.Redaction{WriteOnly: true, Mask: "<redacted>"},
+			keyword:   "maximum",
+			want:      "must be <= 9999 but found 12345",
+		},
+		{
+			name:      "localized",
+			redaction: &jsonschema.Redaction{WriteOnly: true},
+			opts: []jsonschema.ValidationOption{jsonschema.WithCatalog(jsonschema.Catalog{
+				"format": `{{.actual}} ist keine gültige {{.format}}`,
+			})},
+			keyword: "format",
+			want:    "*** ist keine gültige email",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			c.Redaction = tc.redaction
+			c.Extensions["powerOf"] = powerOfExt()
+			if err := c.AddResource("test.json", strings.NewReader(sensitiveSchema)); err != nil {
+				t.Fatal(err)
+			}
+			s, err := c.Compile(ctx, "test.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = s.ValidateInterface(decode(t, sensitiveDoc), tc.opts...)
+			verr, ok := err.(*jsonschema.ValidationError)
+			if !ok {
+				t.Fatalf("got %v, want *ValidationError", err)
+			}
+			if got := verr.ByKeyword(tc.keyword)[0].Message; got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRedactionPropertyNames(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	c.Redaction = &jsonschema.Redaction{Keyword: "x-sensitive"}
+	if err := c.AddResource("test.json", strings.NewReader(`{
+		"propertyNames": {"x-sensitive": true, "pattern": "^[a-z]+$"}
+	}`)); err != nil {
+		t.Fatal(err)
+	}
+	s, err := c.Compile(ctx, "test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonschema.RegisterCatalog("de", jsonschema.Catalog{"pattern": `{{.actual}} passt nicht zu {{quote .pattern}}`})
+	defer jsonschema.RegisterCatalog("de", nil)
+	err = s.ValidateInterface(decode(t, `{"S3cret": 1}`), jsonschema.WithLanguage("de"))
+	if err == nil {
+		t.Fatal("validation must fail")
+	}
+	want := `I[#/%2A%2A%2A] S[test.json#/propertyNames/pattern] *** passt nicht zu "^[a-z]+$"
+`
+	if got := jsonschematest.Render(err.(*jsonschema.ValidationError)); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"io"
 	"net/url"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -20,6 +21,7 @@ type resource struct {
 	doc     interface{}
 	draft   *Draft
 	schemas map[string]*Schema
+	strict  bool // tells whether doc was checked for unknown keywords.
 }
 
 // DecodeJSON decodes json document from r.
@@ -234,3 +236,84 @@ func resolveIDs(draft *Draft, base string, v interface{}, ids map[string]map[str
 
 	return nil
 }
+
+// walkSchemas calls fn for the schema v and all its subschemas, in document
+// order of the keywords. ptr is the json-pointer to v, and base is the url
+// against which the "$ref" of the schema passed to fn must be resolved.
+func walkSchemas(draft *Draft, base, ptr string, v interface{}, fn func(base, ptr string, m map[string]interface{}) error) error {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if id, ok := m[draft.id].(string); ok {
+		b, err := resolveURL(base, id)
+		if err != nil {
+			return err
+		}
+		base = b
+	}
+	if err := fn(base, ptr, m); err != nil {
+		return err
+	}
+	walk := func(ptr string, v interface{}) error {
+		return walkSchemas(draft, base, ptr, v, fn)
+	}
+
+	for _, pname := range schemaKeywords(draft) {
+		pvalue, ok := m[pname]
+		if !ok {
+			continue
+		}
+		ptr := ptr + "/" + escape(pname)
+		switch pname {
+		case "allOf", "anyOf", "oneOf", "items":
+			if arr, ok := pvalue.([]interface{}); ok {
+				for i, item := range arr {
+					if err := walk(ptr+"/"+strconv.Itoa(i), item); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+		case "definitions", "properties", "patternProperties", "dependencies":
+			if props, ok := pvalue.(map[string]interface{}); ok {
+				for _, name := range sortedKeys(props) {
+					if err := walk(ptr+"/"+escape(name), props[name]); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+		if err := walk(ptr, pvalue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schemaKeywords returns the keywords of given draft, whose values are
+// subschemas or contain subschemas.
+func schemaKeywords(draft *Draft) []string {
+	keywords := []string{
+		"definitions", "not", "allOf", "anyOf", "oneOf",
+		"properties", "patternProperties", "additionalProperties", "dependencies",
+		"items", "additionalItems",
+	}
+	if draft.version >= 6 {
+		keywords = append(keywords, "propertyNames", "contains")
+	}
+	if draft.version >= 7 {
+		keywords = append(keywords, "if", "then", "else")
+	}
+	return keywords
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
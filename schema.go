@@ -5,6 +5,7 @@
 package jsonschema
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -12,9 +13,12 @@ import (
 	"math/big"
 	"net/url"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode/utf8"
+
+	"github.com/ory/jsonschema/v3/internal/jsonvalue"
 )
 
 // A Schema represents compiled version of json-schema.
@@ -23,21 +27,20 @@ type Schema struct {
 	Ptr string // json-pointer to schema. always starts with `#`.
 
 	// type agnostic validations
-	format    func(interface{}) bool
-	Format    string
-	Always    *bool         // always pass/fail. used when booleans are used as schemas in draft-07.
-	Ref       *Schema       // reference to actual schema. if not nil, all the remaining fields are ignored.
-	Types     []string      // allowed types.
-	Constant  []interface{} // first element in slice is constant value. note: slice is used to capture nil constant.
-	Enum      []interface{} // allowed values.
-	enumError string        // error message for enum fail. captured here to avoid constructing error message every time.
-	Not       *Schema
-	AllOf     []*Schema
-	AnyOf     []*Schema
-	OneOf     []*Schema
-	If        *Schema
-	Then      *Schema // nil, when If is nil.
-	Else      *Schema // nil, when If is nil.
+	format   func(interface{}) bool
+	Format   string
+	Always   *bool         // always pass/fail. used when booleans are used as schemas in draft-07.
+	Ref      *Schema       // reference to actual schema. if not nil, all the remaining fields are ignored.
+	Types    []string      // allowed types.
+	Constant []interface{} // first element in slice is constant value. note: slice is used to capture nil constant.
+	Enum     []interface{} // allowed values.
+	Not      *Schema
+	AllOf    []*Schema
+	AnyOf    []*Schema
+	OneOf    []*Schema
+	If       *Schema
+	Then     *Schema // nil, when If is nil.
+	Else     *Schema // nil, when If is nil.
 
 	// object validations
 	MinProperties        int      // -1 if not specified.
@@ -82,6 +85,14 @@ type Schema struct {
 	WriteOnly   bool
 	Examples    []interface{}
 
+	// custom error messages. compiled only when Compiler.ErrorMessages is true.
+	ErrorMessage *ErrorMessage
+
+	readOnly  bool       // captured always, unlike ReadOnly, see WithDirection.
+	writeOnly bool       // captured always, unlike WriteOnly, see WithDirection.
+	redaction *Redaction // nil, if values are not redacted.
+	sensitive bool       // values are redacted.
+
 	// user defined extensions
 	Extensions map[string]interface{}
 	extensions map[string]func(ctx ValidationContext, s interface{}, v interface{}) error
@@ -113,19 +124,19 @@ func CompileString(ctx context.Context, url, schema string) (*Schema, error) {
 // Validate validates the given json data, against the json-schema.
 //
 // Returned error can be *ValidationError.
-func (s *Schema) Validate(r io.Reader) error {
+func (s *Schema) Validate(r io.Reader, opts ...ValidationOption) error {
 	doc, err := DecodeJSON(r)
 	if err != nil {
 		return err
 	}
-	return s.ValidateInterface(doc)
+	return s.ValidateInterface(doc, opts...)
 }
 
 // ValidateInterface validates given doc, against the json-schema.
 //
 // the doc must be the value decoded by json package using interface{} type.
 // we recommend to use jsonschema.DecodeJSON(io.Reader) to decode JSON.
-func (s *Schema) ValidateInterface(doc interface{}) (err error) {
+func (s *Schema) ValidateInterface(doc interface{}, opts ...ValidationOption) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			if _, ok := r.(InvalidJSONTypeError); ok {
@@ -135,33 +146,48 @@ func (s *Schema) ValidateInterface(doc interface{}) (err error) {
 			}
 		}
 	}()
-	if err := s.validate(doc); err != nil {
+	o := newValidationOptions(opts)
+	defer o.strip()
+	if err := s.validate(doc, o); err != nil {
 		finishSchemaContext(err, s)
 		finishInstanceContext(err)
+		if s.redaction != nil {
+			s.redaction.redact(err.(*ValidationError))
+		}
+		localize(err.(*ValidationError), o.catalog)
 		return err
 	}
 	return nil
 }
 
 // validate validates given value v with this schema.
-func (s *Schema) validate(v interface{}) error {
+func (s *Schema) validate(v interface{}, o *validationOptions) error {
+	if o.forbids(s) {
+		return o.forbiddenError()
+	}
+	err := s.validateKeywords(v, o)
+	if err != nil && s.ErrorMessage != nil {
+		s.ErrorMessage.apply(err)
+	}
+	if err != nil && s.sensitive {
+		err.(*ValidationError).markSensitive()
+	}
+	return err
+}
+
+func (s *Schema) validateKeywords(v interface{}, o *validationOptions) error {
 	if s.Always != nil {
 		if !*s.Always {
-			return validationErrorf("", "always fail")
+			return keywordError("")
 		}
 		return nil
 	}
 
 	if s.Ref != nil {
-		if err := s.Ref.validate(v); err != nil {
+		if err := s.Ref.validate(v, o); err != nil {
 			finishSchemaContext(err, s.Ref)
-			var refURL string
-			if s.URL == s.Ref.URL {
-				refURL = s.Ref.Ptr
-			} else {
-				refURL = s.Ref.URL + s.Ref.Ptr
-			}
-			return validationErrorf("$ref", "doesn't validate with %q", refURL).add(err)
+			return keywordError("$ref").
+				withContext(&ValidationErrorContextRef{Ref: s.Ref.URL + s.Ref.Ptr}).add(err)
 		}
 
 		// All other properties in a "$ref" object MUST be ignored
@@ -183,7 +209,8 @@ func (s *Schema) validate(v interface{}) error {
 			}
 		}
 		if !matched {
-			return validationErrorf("type", "expected %s, but got %s", strings.Join(s.Types, " or "), vType)
+			return keywordError("type").
+				withContext(&ValidationErrorContextType{Expected: s.Types, Actual: vType})
 		}
 	}
 
@@ -191,12 +218,7 @@ func (s *Schema) validate(v interface{}) error {
 
 	if len(s.Constant) > 0 {
 		if !equals(v, s.Constant[0]) {
-			switch jsonType(s.Constant[0]) {
-			case "object", "array":
-				errors = append(errors, validationErrorf("const", "const failed"))
-			default:
-				errors = append(errors, validationErrorf("const", "value must be %#v", s.Constant[0]))
-			}
+			errors = append(errors, keywordError("const").withContext(&ValidationErrorContextConst{Expected: s.Constant[0], Actual: v}))
 		}
 	}
 
@@ -209,21 +231,28 @@ func (s *Schema) validate(v interface{}) error {
 			}
 		}
 		if !matched {
-			errors = append(errors, validationErrorf("enum", "%s", s.enumError))
+			errors = append(errors, keywordError("enum").
+				withContext(&ValidationErrorContextEnum{Expected: s.Enum, Actual: v}))
 		}
 	}
 
 	if s.format != nil && !s.format(v) {
-		errors = append(errors, validationErrorf("format", "%q is not valid %q", v, s.Format))
+		errors = append(errors, keywordError("format").
+			withContext(&ValidationErrorContextFormat{Format: s.Format, Actual: v}))
 	}
 
-	if s.Not != nil && s.Not.validate(v) == nil {
-		errors = append(errors, validationErrorf("not", "not failed"))
+	if s.Not != nil {
+		mark := o.stripMark()
+		if s.Not.validate(v, o) == nil {
+			errors = append(errors, keywordError("not").withContext(&ValidationErrorContextNot{}))
+		}
+		o.unstrip(mark)
 	}
 
 	for i, sch := range s.AllOf {
-		if err := sch.validate(v); err != nil {
-			errors = append(errors, validationErrorf("allOf/"+strconv.Itoa(i), "allOf failed").add(err))
+		if err := sch.validate(v, o); err != nil {
+			errors = append(errors, keywordError("allOf/"+strconv.Itoa(i)).
+				withContext(&ValidationErrorContextAllOf{Index: i}).add(err))
 		}
 	}
 
@@ -231,15 +260,18 @@ func (s *Schema) validate(v interface{}) error {
 		matched := false
 		var causes []error
 		for i, sch := range s.AnyOf {
-			if err := sch.validate(v); err == nil {
+			mark := o.stripMark()
+			if err := sch.validate(v, o); err == nil {
 				matched = true
 				break
 			} else {
+				o.unstrip(mark)
 				causes = append(causes, addContext("", strconv.Itoa(i), err))
 			}
 		}
 		if !matched {
-			errors = append(errors, validationErrorf("anyOf", "anyOf failed").add(causes...))
+			errors = append(errors, keywordError("anyOf").
+				withContext(&ValidationErrorContextAnyOf{}).add(causes...))
 		}
 	}
 
@@ -247,33 +279,43 @@ func (s *Schema) validate(v interface{}) error {
 		matched := -1
 		var causes []error
 		for i, sch := range s.OneOf {
-			if err := sch.validate(v); err == nil {
+			mark := o.stripMark()
+			if err := sch.validate(v, o); err == nil {
 				if matched == -1 {
 					matched = i
 				} else {
-					errors = append(errors, validationErrorf("oneOf", "valid against schemas at indexes %d and %d", matched, i))
+					o.unstrip(mark)
+					errors = append(errors, keywordError("oneOf").
+						withContext(&ValidationErrorContextOneOf{Matched: []int{matched, i}}))
 					break
 				}
 			} else {
+				o.unstrip(mark)
 				causes = append(causes, addContext("", strconv.Itoa(i), err))
 			}
 		}
 		if matched == -1 {
-			errors = append(errors, validationErrorf("oneOf", "oneOf failed").add(causes...))
+			errors = append(errors, keywordError("oneOf").
+				withContext(&ValidationErrorContextOneOf{}).add(causes...))
 		}
 	}
 
 	if s.If != nil {
-		if s.If.validate(v) == nil {
+		mark := o.stripMark()
+		valid := s.If.validate(v, o) == nil
+		o.unstrip(mark)
+		if valid {
 			if s.Then != nil {
-				if err := s.Then.validate(v); err != nil {
-					errors = append(errors, validationErrorf("then", "if-then failed").add(err))
+				if err := s.Then.validate(v, o); err != nil {
+					errors = append(errors, keywordError("then").
+						withContext(&ValidationErrorContextThen{}).add(err))
 				}
 			}
 		} else {
 			if s.Else != nil {
-				if err := s.Else.validate(v); err != nil {
-					errors = append(errors, validationErrorf("else", "if-else failed").add(err))
+				if err := s.Else.validate(v, o); err != nil {
+					errors = append(errors, keywordError("else").
+						withContext(&ValidationErrorContextElse{}).add(err))
 				}
 			}
 		}
@@ -281,16 +323,18 @@ func (s *Schema) validate(v interface{}) error {
 
 	switch v := v.(type) {
 	case map[string]interface{}:
-		if s.MinProperties != -1 && len(v) < s.MinProperties {
-			errors = append(errors, validationErrorf("minProperties", "minimum %d properties allowed, but found %d properties", s.MinProperties, len(v)))
+		if s.MinProperties != -1 && len(v) < s.MinProperties && o.checksPresence() {
+			errors = append(errors, keywordError("minProperties").
+				withContext(&ValidationErrorContextMinProperties{Expected: s.MinProperties, Actual: len(v)}))
 		}
 		if s.MaxProperties != -1 && len(v) > s.MaxProperties {
-			errors = append(errors, validationErrorf("maxProperties", "maximum %d properties allowed, but found %d properties", s.MaxProperties, len(v)))
+			errors = append(errors, keywordError("maxProperties").
+				withContext(&ValidationErrorContextMaxProperties{Expected: s.MaxProperties, Actual: len(v)}))
 		}
-		if len(s.Required) > 0 {
+		if len(s.Required) > 0 && o.checksPresence() {
 			var missing []string
 			for _, pname := range s.Required {
-				if _, ok := v[pname]; !ok {
+				if _, ok := v[pname]; !ok && !o.omits(s.Properties[pname]) {
 					missing = append(missing, pname)
 				}
 			}
@@ -307,91 +351,129 @@ func (s *Schema) validate(v interface{}) error {
 			}
 		}
 
+		// properties are evaluated in map order, so the errors of each keyword
+		// are sorted to be reported in the same order on every run.
 		if len(s.Properties) > 0 {
+			n := len(errors)
 			for pname, pschema := range s.Properties {
 				if pvalue, ok := v[pname]; ok {
 					delete(additionalProps, pname)
-					if err := pschema.validate(pvalue); err != nil {
+					if o.strips(v, pname, pschema) {
+						continue
+					}
+					if err := pschema.validate(pvalue, o); err != nil {
 						errors = append(errors, addContext(escape(pname), "properties/"+escape(pname), err))
 					}
 				}
 			}
+			sortErrors(errors[n:])
 		}
 
 		if s.PropertyNames != nil {
+			n := len(errors)
 			for pname := range v {
-				if err := s.PropertyNames.validate(pname); err != nil {
+				if err := s.PropertyNames.validate(pname, o); err != nil {
+					err.(*ValidationError).Walk(func(err *ValidationError) bool {
+						err.propertyName = true
+						return true
+					})
 					errors = append(errors, addContext(escape(pname), "propertyNames", err))
 				}
 			}
+			sortErrors(errors[n:])
 		}
 
 		if s.RegexProperties {
+			n := len(errors)
 			for pname := range v {
 				if !isRegex(pname) {
-					errors = append(errors, validationErrorf("", "patternProperty %q is not valid regex", pname))
+					errors = append(errors, keywordError("").
+						withContext(&ValidationErrorContextRegexProperties{Property: pname}))
 				}
 			}
+			sortErrors(errors[n:])
 		}
-		for pattern, pschema := range s.PatternProperties {
-			for pname, pvalue := range v {
-				if pattern.MatchString(pname) {
-					delete(additionalProps, pname)
-					if err := pschema.validate(pvalue); err != nil {
-						errors = append(errors, addContext(escape(pname), "patternProperties/"+escape(pattern.String()), err))
+		if len(s.PatternProperties) > 0 {
+			n := len(errors)
+			for pattern, pschema := range s.PatternProperties {
+				for pname, pvalue := range v {
+					if pattern.MatchString(pname) {
+						delete(additionalProps, pname)
+						if o.strips(v, pname, pschema) {
+							continue
+						}
+						if err := pschema.validate(pvalue, o); err != nil {
+							errors = append(errors, addContext(escape(pname), "patternProperties/"+escape(pattern.String()), err))
+						}
 					}
 				}
 			}
+			sortErrors(errors[n:])
 		}
 		if s.AdditionalProperties != nil {
 			if _, ok := s.AdditionalProperties.(bool); ok {
 				if len(additionalProps) != 0 {
-					pnames := make([]string, 0, len(additionalProps))
-					for pname := range additionalProps {
-						pnames = append(pnames, strconv.Quote(pname))
+					pnames := sortedKeys(additionalProps)
+					ptrs := make([]string, len(pnames))
+					for i, pname := range pnames {
+						ptrs[i] = escape(pname)
 					}
-					errors = append(errors, validationErrorf("additionalProperties", "additionalProperties %s not allowed", strings.Join(pnames, ", ")))
+					errors = append(errors, keywordError("additionalProperties").
+						withContext(&ValidationErrorContextAdditionalProperties{Properties: ptrs}))
 				}
 			} else {
 				schema := s.AdditionalProperties.(*Schema)
+				n := len(errors)
 				for pname := range additionalProps {
 					if pvalue, ok := v[pname]; ok {
-						if err := schema.validate(pvalue); err != nil {
+						if o.strips(v, pname, schema) {
+							continue
+						}
+						if err := schema.validate(pvalue, o); err != nil {
 							errors = append(errors, addContext(escape(pname), "additionalProperties", err))
 						}
 					}
 				}
+				sortErrors(errors[n:])
 			}
 		}
-		for dname, dvalue := range s.Dependencies {
-			if _, ok := v[dname]; ok {
-				switch dvalue := dvalue.(type) {
-				case *Schema:
-					if err := dvalue.validate(v); err != nil {
-						errors = append(errors, addContext("", "dependencies/"+escape(dname), err))
-					}
-				case []string:
-					for i, pname := range dvalue {
-						if _, ok := v[pname]; !ok {
-							errors = append(errors, validationErrorf("dependencies/"+escape(dname)+"/"+strconv.Itoa(i), "property %q is required, if %q property exists", pname, dname))
+		if len(s.Dependencies) > 0 {
+			n := len(errors)
+			for dname, dvalue := range s.Dependencies {
+				if _, ok := v[dname]; ok {
+					switch dvalue := dvalue.(type) {
+					case *Schema:
+						if err := dvalue.validate(v, o); err != nil {
+							errors = append(errors, addContext("", "dependencies/"+escape(dname), err))
+						}
+					case []string:
+						for i, pname := range dvalue {
+							if _, ok := v[pname]; !ok && o.checksPresence() {
+								errors = append(errors, keywordError("dependencies/"+escape(dname)+"/"+strconv.Itoa(i)).
+									withContext(&ValidationErrorContextDependencies{Property: dname, Missing: escape(pname)}))
+							}
 						}
 					}
 				}
 			}
+			sortErrors(errors[n:])
 		}
 
 	case []interface{}:
 		if s.MinItems != -1 && len(v) < s.MinItems {
-			errors = append(errors, validationErrorf("minItems", "minimum %d items allowed, but found %d items", s.MinItems, len(v)))
+			errors = append(errors, keywordError("minItems").
+				withContext(&ValidationErrorContextMinItems{Expected: s.MinItems, Actual: len(v)}))
 		}
 		if s.MaxItems != -1 && len(v) > s.MaxItems {
-			errors = append(errors, validationErrorf("maxItems", "maximum %d items allowed, but found %d items", s.MaxItems, len(v)))
+			errors = append(errors, keywordError("maxItems").
+				withContext(&ValidationErrorContextMaxItems{Expected: s.MaxItems, Actual: len(v)}))
 		}
 		if s.UniqueItems {
 			for i := 1; i < len(v); i++ {
 				for j := 0; j < i; j++ {
 					if equals(v[i], v[j]) {
-						errors = append(errors, validationErrorf("uniqueItems", "items at index %d and %d are equal", j, i))
+						errors = append(errors, keywordError("uniqueItems").
+							withContext(&ValidationErrorContextUniqueItems{Indexes: [2]int{j, i}}))
 					}
 				}
 			}
@@ -399,23 +481,24 @@ func (s *Schema) validate(v interface{}) error {
 		switch items := s.Items.(type) {
 		case *Schema:
 			for i, item := range v {
-				if err := items.validate(item); err != nil {
+				if err := items.validate(item, o); err != nil {
 					errors = append(errors, addContext(strconv.Itoa(i), "items", err))
 				}
 			}
 		case []*Schema:
 			if additionalItems, ok := s.AdditionalItems.(bool); ok {
 				if !additionalItems && len(v) > len(items) {
-					errors = append(errors, validationErrorf("additionalItems", "only %d items are allowed, but found %d items", len(items), len(v)))
+					errors = append(errors, keywordError("additionalItems").
+						withContext(&ValidationErrorContextAdditionalItems{Expected: len(items), Actual: len(v)}))
 				}
 			}
 			for i, item := range v {
 				if i < len(items) {
-					if err := items[i].validate(item); err != nil {
+					if err := items[i].validate(item, o); err != nil {
 						errors = append(errors, addContext(strconv.Itoa(i), "items/"+strconv.Itoa(i), err))
 					}
 				} else if sch, ok := s.AdditionalItems.(*Schema); ok {
-					if err := sch.validate(item); err != nil {
+					if err := sch.validate(item, o); err != nil {
 						errors = append(errors, addContext(strconv.Itoa(i), "additionalItems", err))
 					}
 				} else {
@@ -427,7 +510,9 @@ func (s *Schema) validate(v interface{}) error {
 			matched := false
 			var causes []error
 			for i, item := range v {
-				if err := s.Contains.validate(item); err != nil {
+				mark := o.stripMark()
+				if err := s.Contains.validate(item, o); err != nil {
+					o.unstrip(mark)
 					causes = append(causes, addContext(strconv.Itoa(i), "", err))
 				} else {
 					matched = true
@@ -435,7 +520,8 @@ func (s *Schema) validate(v interface{}) error {
 				}
 			}
 			if !matched {
-				errors = append(errors, validationErrorf("contains", "contains failed").add(causes...))
+				errors = append(errors, keywordError("contains").
+					withContext(&ValidationErrorContextContains{}).add(causes...))
 			}
 		}
 
@@ -443,14 +529,17 @@ func (s *Schema) validate(v interface{}) error {
 		if s.MinLength != -1 || s.MaxLength != -1 {
 			length := utf8.RuneCount([]byte(v))
 			if s.MinLength != -1 && length < s.MinLength {
-				errors = append(errors, validationErrorf("minLength", "length must be >= %d, but got %d", s.MinLength, length))
+				errors = append(errors, keywordError("minLength").
+					withContext(&ValidationErrorContextMinLength{Expected: s.MinLength, Actual: length}))
 			}
 			if s.MaxLength != -1 && length > s.MaxLength {
-				errors = append(errors, validationErrorf("maxLength", "length must be <= %d, but got %d", s.MaxLength, length))
+				errors = append(errors, keywordError("maxLength").
+					withContext(&ValidationErrorContextMaxLength{Expected: s.MaxLength, Actual: length}))
 			}
 		}
 		if s.Pattern != nil && !s.Pattern.MatchString(v) {
-			errors = append(errors, validationErrorf("pattern", "does not match pattern %q", s.Pattern))
+			errors = append(errors, keywordError("pattern").
+				withContext(&ValidationErrorContextPattern{Pattern: s.Pattern.String(), Actual: v}))
 		}
 
 		decoded := s.ContentEncoding == ""
@@ -458,7 +547,8 @@ func (s *Schema) validate(v interface{}) error {
 		if s.decoder != nil {
 			b, err := s.decoder(v)
 			if err != nil {
-				errors = append(errors, validationErrorf("contentEncoding", "%q is not %s encoded", v, s.ContentEncoding))
+				errors = append(errors, keywordError("contentEncoding").
+					withContext(&ValidationErrorContextContentEncoding{Encoding: s.ContentEncoding, Actual: v}))
 			} else {
 				content, decoded = b, true
 			}
@@ -468,36 +558,46 @@ func (s *Schema) validate(v interface{}) error {
 				content = []byte(v)
 			}
 			if err := s.mediaType(content); err != nil {
-				errors = append(errors, validationErrorf("contentMediaType", "value is not of mediatype %q", s.ContentMediaType))
+				errors = append(errors, keywordError("contentMediaType").
+					withContext(&ValidationErrorContextContentMediaType{MediaType: s.ContentMediaType}))
 			}
 		}
 
 	case json.Number, float64, int, int32, int64:
 		num, _ := new(big.Float).SetString(fmt.Sprint(v))
 		if s.Minimum != nil && num.Cmp(s.Minimum) < 0 {
-			errors = append(errors, validationErrorf("minimum", "must be >= %v but found %v", s.Minimum, v))
+			errors = append(errors, keywordError("minimum").
+				withContext(&ValidationErrorContextMinimum{Expected: s.Minimum, Actual: v}))
 		}
 		if s.ExclusiveMinimum != nil && num.Cmp(s.ExclusiveMinimum) <= 0 {
-			errors = append(errors, validationErrorf("exclusiveMinimum", "must be > %v but found %v", s.ExclusiveMinimum, v))
+			errors = append(errors, keywordError("exclusiveMinimum").
+				withContext(&ValidationErrorContextExclusiveMinimum{Expected: s.ExclusiveMinimum, Actual: v}))
 		}
 		if s.Maximum != nil && num.Cmp(s.Maximum) > 0 {
-			errors = append(errors, validationErrorf("maximum", "must be <= %v but found %v", s.Maximum, v))
+			errors = append(errors, keywordError("maximum").
+				withContext(&ValidationErrorContextMaximum{Expected: s.Maximum, Actual: v}))
 		}
 		if s.ExclusiveMaximum != nil && num.Cmp(s.ExclusiveMaximum) >= 0 {
-			errors = append(errors, validationErrorf("exclusiveMaximum", "must be < %v but found %v", s.ExclusiveMaximum, v))
+			errors = append(errors, keywordError("exclusiveMaximum").
+				withContext(&ValidationErrorContextExclusiveMaximum{Expected: s.ExclusiveMaximum, Actual: v}))
 		}
 		if s.MultipleOf != nil {
 			if q := new(big.Float).Quo(num, s.MultipleOf); !q.IsInt() {
-				errors = append(errors, validationErrorf("multipleOf", "%v not multipleOf %v", v, s.MultipleOf))
+				errors = append(errors, keywordError("multipleOf").
+					withContext(&ValidationErrorContextMultipleOf{Expected: s.MultipleOf, Actual: v}))
 			}
 		}
 	}
 
-	for name, cs := range s.Extensions {
-		validate := s.extensions[name]
-		if err := validate(ValidationContext{}, cs, v); err != nil {
-			errors = append(errors, err)
+	if len(s.Extensions) > 0 {
+		n := len(errors)
+		for name, cs := range s.Extensions {
+			validate := s.extensions[name]
+			if err := validate(ValidationContext{o}, cs, v); err != nil {
+				errors = append(errors, err)
+			}
 		}
+		sortErrors(errors[n:])
 	}
 
 	switch len(errors) {
@@ -506,71 +606,62 @@ func (s *Schema) validate(v interface{}) error {
 	case 1:
 		return errors[0]
 	default:
-		return validationErrorf("", "validation failed").add(errors...)
+		return keywordError("").add(errors...)
+	}
+}
+
+// sortErrors sorts errs by instance pointer and then by schema pointer.
+func sortErrors(errs []error) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		ei, ej := errs[i].(*ValidationError), errs[j].(*ValidationError)
+		if c := comparePtrs(ei.InstancePtr, ej.InstancePtr); c != 0 {
+			return c < 0
+		}
+		if c := comparePtrs(ei.SchemaPtr, ej.SchemaPtr); c != 0 {
+			return c < 0
+		}
+		// errors of regexProperties differ only in the property.
+		pi, _ := ei.Context.(*ValidationErrorContextRegexProperties)
+		pj, _ := ej.Context.(*ValidationErrorContextRegexProperties)
+		return pi != nil && pj != nil && pi.Property < pj.Property
+	})
+}
+
+// comparePtrs compares json-pointers p1 and p2 token by token. Array indexes
+// are compared as numbers.
+func comparePtrs(p1, p2 string) int {
+	t1, t2 := strings.Split(p1, "/"), strings.Split(p2, "/")
+	for i := 0; i < len(t1) && i < len(t2); i++ {
+		if t1[i] == t2[i] {
+			continue
+		}
+		n1, err1 := strconv.Atoi(t1[i])
+		n2, err2 := strconv.Atoi(t2[i])
+		if err1 == nil && err2 == nil && n1 != n2 {
+			return cmp.Compare(n1, n2)
+		}
+		return strings.Compare(t1[i], t2[i])
 	}
+	return cmp.Compare(len(t1), len(t2))
 }
 
 // jsonType returns the json type of given value v.
 //
 // It panics if the given value is not valid json value
 func jsonType(v interface{}) string {
-	switch v.(type) {
-	case nil:
-		return "null"
-	case bool:
-		return "boolean"
-	case json.Number, float64, int, int32, int64:
-		return "number"
-	case string:
-		return "string"
-	case []interface{}:
-		return "array"
-	case map[string]interface{}:
-		return "object"
+	if t := jsonvalue.Type(v); t != "" {
+		return t
 	}
 	panic(InvalidJSONTypeError(fmt.Sprintf("%T", v)))
 }
 
 // equals tells if given two json values are equal or not.
+//
+// It panics if any of the given values is not valid json value
 func equals(v1, v2 interface{}) bool {
-	v1Type := jsonType(v1)
-	if v1Type != jsonType(v2) {
-		return false
-	}
-	switch v1Type {
-	case "array":
-		arr1, arr2 := v1.([]interface{}), v2.([]interface{})
-		if len(arr1) != len(arr2) {
-			return false
-		}
-		for i := range arr1 {
-			if !equals(arr1[i], arr2[i]) {
-				return false
-			}
-		}
-		return true
-	case "object":
-		obj1, obj2 := v1.(map[string]interface{}), v2.(map[string]interface{})
-		if len(obj1) != len(obj2) {
-			return false
-		}
-		for k, v1 := range obj1 {
-			if v2, ok := obj2[k]; ok {
-				if !equals(v1, v2) {
-					return false
-				}
-			} else {
-				return false
-			}
-		}
-		return true
-	case "number":
-		num1, _ := new(big.Float).SetString(string(v1.(json.Number)))
-		num2, _ := new(big.Float).SetString(string(v2.(json.Number)))
-		return num1.Cmp(num2) == 0
-	default:
-		return v1 == v2
-	}
+	jsonType(v1)
+	jsonType(v2)
+	return jsonvalue.Equal(v1, v2)
 }
 
 // escape converts given token to valid json-pointer token
@@ -579,3 +670,13 @@ func escape(token string) string {
 	token = strings.Replace(token, "/", "~1", -1)
 	return url.PathEscape(token)
 }
+
+// unescape converts given json-pointer token, escaped by escape, back to the
+// token.
+func unescape(token string) string {
+	if t, err := url.PathUnescape(token); err == nil {
+		token = t
+	}
+	token = strings.Replace(token, "~1", "/", -1)
+	return strings.Replace(token, "~0", "~", -1)
+}
@@ -572,3 +572,79 @@ func TestSchemaReferencesDrafts(t *testing.T) {
 		t.Fatalf("compile should not error. reason: %v\n", err)
 	}
 }
+
+func TestErrorOrder(t *testing.T) {
+	s, err := jsonschema.CompileString(ctx, "test.json", `{
+		"properties": {"c": {"type": "string"}, "a": {"type": "string"}, "b": {"type": "string"}},
+		"patternProperties": {"^x": {"minimum": 10}, "^xy": {"maximum": 0}},
+		"additionalProperties": false,
+		"dependencies": {"c": ["d"], "a": ["d"]},
+		"propertyNames": {"maxLength": 2}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `I[#] S[#] validation failed
+  I[#/a] S[#/properties/a/type] expected string, but got number
+  I[#/b] S[#/properties/b/type] expected string, but got number
+  I[#/c] S[#/properties/c/type] expected string, but got number
+  I[#/zzz] S[#/propertyNames/maxLength] length must be <= 2, but got 3
+  I[#/x1] S[#/patternProperties/%5Ex/minimum] must be >= 10 but found 1
+  I[#/xy] S[#/patternProperties/%5Ex/minimum] must be >= 10 but found 2
+  I[#/xy] S[#/patternProperties/%5Exy/maximum] must be <= 0 but found 2
+  I[#] S[#/additionalProperties] additionalProperties "y", "zzz" not allowed
+  I[#] S[#/dependencies/a/0] property "d" is required, if "a" property exists
+  I[#] S[#/dependencies/c/0] property "d" is required, if "c" property exists`
+	for i := 0; i < 20; i++ {
+		err := s.Validate(strings.NewReader(`{"zzz": 1, "y": 1, "xy": 2, "x1": 1, "c": 1, "b": 1, "a": 1}`))
+		if err == nil {
+			t.Fatal("validation must fail")
+		}
+		if got := err.Error(); got != want {
+			t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+		}
+	}
+}
+
+func TestErrorOrderArray(t *testing.T) {
+	s, err := jsonschema.CompileString(ctx, "test.json", `{
+		"items": {"type": "string"},
+		"properties": {"11": {"minimum": 1}, "2": {"minimum": 1}},
+		"minItems": 20
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `I[#] S[#] validation failed
+  I[#] S[#/minItems] minimum 20 items allowed, but found 12 items
+  I[#/0] S[#/items/type] expected string, but got number
+  I[#/1] S[#/items/type] expected string, but got number
+  I[#/2] S[#/items/type] expected string, but got number
+  I[#/3] S[#/items/type] expected string, but got number
+  I[#/4] S[#/items/type] expected string, but got number
+  I[#/5] S[#/items/type] expected string, but got number
+  I[#/6] S[#/items/type] expected string, but got number
+  I[#/7] S[#/items/type] expected string, but got number
+  I[#/8] S[#/items/type] expected string, but got number
+  I[#/9] S[#/items/type] expected string, but got number
+  I[#/10] S[#/items/type] expected string, but got number
+  I[#/11] S[#/items/type] expected string, but got number`
+	err = s.Validate(strings.NewReader(`[0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11]`))
+	if err == nil {
+		t.Fatal("validation must fail")
+	}
+	if got := err.Error(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	err = s.Validate(strings.NewReader(`{"2": 0, "11": 0}`))
+	want = `I[#] S[#] validation failed
+  I[#/2] S[#/properties/2/minimum] must be >= 1 but found 0
+  I[#/11] S[#/properties/11/minimum] must be >= 1 but found 0`
+	if err == nil {
+		t.Fatal("validation must fail")
+	}
+	if got := err.Error(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
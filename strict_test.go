@@ -0,0 +1,95 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+func TestCompiler_Strict(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"x-internal": true,
+		"powerOf": 2,
+		"properties": {
+			"name": {"type": "string", "maxLenght": 10},
+			"tags": {"type": "array", "items": {"type": "string", "uniqe": true}}
+		}
+	}`
+	newCompiler := func(strict bool, allowed ...string) *jsonschema.Compiler {
+		c := jsonschema.NewCompiler()
+		c.Strict = strict
+		c.AllowedKeywords = allowed
+		if err := c.AddResource("test.json", strings.NewReader(schema)); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	t.Run("lenient", func(t *testing.T) {
+		if _, err := newCompiler(false).Compile(ctx, "test.json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		_, err := newCompiler(true, "x-*").Compile(ctx, "test.json")
+		serr, ok := err.(*jsonschema.SchemaError)
+		if !ok {
+			t.Fatalf("want *SchemaError, got %#v", err)
+		}
+		kerr, ok := serr.Err.(*jsonschema.UnknownKeywordsError)
+		if !ok {
+			t.Fatalf("want *UnknownKeywordsError, got %#v", serr.Err)
+		}
+		if len(kerr.Keywords) != 3 {
+			t.Errorf("want 3 schemas with unknown keywords, got %v", kerr.Keywords)
+		}
+		if got := kerr.Keywords["#/properties/name"]; len(got) != 1 || got[0] != "maxLenght" {
+			t.Errorf("#/properties/name: got %v", got)
+		}
+		if got := kerr.Keywords["#/properties/tags/items"]; len(got) != 1 || got[0] != "uniqe" {
+			t.Errorf("#/properties/tags/items: got %v", got)
+		}
+		if got := kerr.Keywords["#"]; len(got) != 1 || got[0] != "powerOf" {
+			t.Errorf("#: got %v", got)
+		}
+		t.Log(err)
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		if _, err := newCompiler(true, "x-*", "maxLenght", "uniqe", "powerOf").Compile(ctx, "test.json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("extension", func(t *testing.T) {
+		c := newCompiler(true, "x-*", "maxLenght", "uniqe")
+		if _, err := c.Compile(ctx, "test.json"); err == nil {
+			t.Fatal("error expected for powerOf")
+		}
+		c = newCompiler(true, "x-*", "maxLenght", "uniqe")
+		c.Extensions["powerOf"] = powerOfExt()
+		if _, err := c.Compile(ctx, "test.json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("readOnly", func(t *testing.T) {
+		// readOnly and writeOnly are enforced for every draft, see WithDirection.
+		for _, draft := range []string{"draft-04", "draft-06", "draft-07"} {
+			c := jsonschema.NewCompiler()
+			c.Strict = true
+			if err := c.AddResource("test.json", strings.NewReader(`{
+				"$schema": "http://json-schema.org/`+draft+`/schema#",
+				"properties": {"id": {"readOnly": true}, "password": {"writeOnly": true}}
+			}`)); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.Compile(ctx, "test.json"); err != nil {
+				t.Errorf("%s: %v", draft, err)
+			}
+		}
+	})
+}
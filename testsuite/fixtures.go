@@ -0,0 +1,224 @@
+package testsuite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+// ManifestFile is the name of the file describing the fixtures of a
+// directory.
+const ManifestFile = "manifest.json"
+
+// Manifest describes a schema and its fixtures. Paths are relative to the
+// directory of the manifest.
+//
+//	{
+//		"schema": "user.schema.json",
+//		"valid": ["valid/*.json"],
+//		"invalid": [
+//			"invalid/*.json",
+//			{"file": "invalid/age.json", "instancePtr": "#/age", "schemaPtr": "#/properties/age/minimum"}
+//		]
+//	}
+//
+// Directories without manifest follow the convention of DefaultManifest. A
+// pattern, which matches no files, is reported as a failed test.
+type Manifest struct {
+	Schema string `json:"schema"`
+
+	// Valid lists glob patterns of the fixtures, which must be valid.
+	Valid []string `json:"valid"`
+
+	// Invalid lists the fixtures, which must be invalid.
+	Invalid []Expectation `json:"invalid"`
+}
+
+// DefaultManifest is used for directories without ManifestFile, which have
+// schema.json file.
+var DefaultManifest = Manifest{
+	Schema:  "schema.json",
+	Valid:   []string{"valid/*.json"},
+	Invalid: []Expectation{{File: "invalid/*.json"}},
+}
+
+// Expectation describes invalid fixtures. In manifest, it is either a glob
+// pattern or an object.
+type Expectation struct {
+	// File is glob pattern of the fixtures.
+	File string `json:"file"`
+
+	// InstancePtr and SchemaPtr, if not empty, must match the InstancePtr
+	// and SchemaPtr of the validation error or of any of its causes.
+	InstancePtr string `json:"instancePtr,omitempty"`
+	SchemaPtr   string `json:"schemaPtr,omitempty"`
+}
+
+// UnmarshalJSON accepts a glob pattern in place of the object.
+func (e *Expectation) UnmarshalJSON(data []byte) error {
+	var file string
+	if err := json.Unmarshal(data, &file); err == nil {
+		*e = Expectation{File: file}
+		return nil
+	}
+	type expectation Expectation
+	return json.Unmarshal(data, (*expectation)(e))
+}
+
+// ReadManifest returns the manifest of the fixtures in dir. ok is false, if
+// dir has neither ManifestFile nor the schema of DefaultManifest.
+func ReadManifest(dir string) (m *Manifest, ok bool, err error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		if _, err := os.Stat(filepath.Join(dir, DefaultManifest.Schema)); err != nil {
+			return nil, false, nil
+		}
+		m := DefaultManifest
+		return &m, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	m = &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, false, fmt.Errorf("testsuite: invalid manifest %s: %w", filepath.Join(dir, ManifestFile), err)
+	}
+	return m, true, nil
+}
+
+// RunFixtures runs the fixtures of every directory in dir and its
+// subdirectories, which has a manifest as described by ReadManifest. Each
+// such directory is reported as a file with groups "valid" and "invalid",
+// whose tests are named by fixture path.
+func (r *Runner) RunFixtures(ctx context.Context, dir string) (*Report, error) {
+	report := &Report{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		m, ok, err := ReadManifest(path)
+		if err != nil || !ok {
+			return err
+		}
+		f, err := r.runManifest(ctx, path, m)
+		if err != nil {
+			return err
+		}
+		report.Files = append(report.Files, f)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (r *Runner) runManifest(ctx context.Context, dir string, m *Manifest) (*FileResult, error) {
+	valid := &GroupResult{Description: "valid"}
+	invalid := &GroupResult{Description: "invalid"}
+	result := &FileResult{Path: dir, Groups: []*GroupResult{valid, invalid}}
+
+	for _, pattern := range m.Valid {
+		files, err := glob(dir, pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			valid.Tests = append(valid.Tests, noMatch(dir, pattern, true))
+		}
+		for _, file := range files {
+			valid.Tests = append(valid.Tests, &TestResult{Description: file, Valid: true})
+		}
+	}
+	var expectations []Expectation
+	for _, e := range m.Invalid {
+		files, err := glob(dir, e.File)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			invalid.Tests = append(invalid.Tests, noMatch(dir, e.File, false))
+			expectations = append(expectations, e)
+		}
+		for _, file := range files {
+			invalid.Tests = append(invalid.Tests, &TestResult{Description: file})
+			expectations = append(expectations, e)
+		}
+	}
+
+	schema, err := r.newCompiler().Compile(ctx, filepath.Join(dir, m.Schema))
+	if err != nil {
+		valid.Err, invalid.Err = err, err
+		return result, nil
+	}
+	for _, t := range valid.Tests {
+		if t.Mismatch == "" {
+			t.Err = validateFile(schema, t.Description)
+		}
+	}
+	for i, t := range invalid.Tests {
+		if t.Mismatch != "" {
+			continue
+		}
+		t.Err = validateFile(schema, t.Description)
+		t.Mismatch = expectations[i].mismatch(t.Err)
+	}
+	return result, nil
+}
+
+// glob returns the sorted files matching pattern relative to dir.
+func glob(dir, pattern string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return nil, fmt.Errorf("testsuite: invalid pattern %q: %w", pattern, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// noMatch returns the failed test reported for pattern, which matches no
+// files, so that a mistyped pattern does not skip its fixtures silently.
+func noMatch(dir, pattern string, valid bool) *TestResult {
+	return &TestResult{Description: filepath.Join(dir, pattern), Valid: valid, Mismatch: "no fixtures match the pattern"}
+}
+
+func validateFile(schema *jsonschema.Schema, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return schema.Validate(f)
+}
+
+// mismatch describes how err differs from the expectation, or returns empty
+// string if it does not.
+func (e Expectation) mismatch(err error) string {
+	if e.InstancePtr == "" && e.SchemaPtr == "" {
+		return ""
+	}
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return ""
+	}
+	matched := false
+	verr.Walk(func(err *jsonschema.ValidationError) bool {
+		if (e.InstancePtr == "" || e.InstancePtr == err.InstancePtr) && (e.SchemaPtr == "" || e.SchemaPtr == err.SchemaPtr) {
+			matched = true
+		}
+		return !matched
+	})
+	if matched {
+		return ""
+	}
+	got := "    " + strings.ReplaceAll(verr.Error(), "\n", "\n    ")
+	return fmt.Sprintf("expected error at I[%s] S[%s], got:\n%s", e.InstancePtr, e.SchemaPtr, got)
+}
@@ -0,0 +1,267 @@
+// Package testsuite runs test files in the format of the official
+// JSON-Schema-Test-Suite (https://github.com/json-schema-org/JSON-Schema-Test-Suite)
+// against a configured Compiler.
+//
+// Each file holds an array of groups, each with a schema and tests:
+//
+//	[{
+//		"description": "powerOf",
+//		"schema": {"powerOf": 10},
+//		"tests": [
+//			{"description": "power of 10", "data": 100, "valid": true},
+//			{"description": "not power of 10", "data": 42, "valid": false}
+//		]
+//	}]
+//
+// This allows testing custom keywords, formats and loaders the same way
+// the spec tests the standard keywords:
+//
+//	r := &testsuite.Runner{NewCompiler: func() *jsonschema.Compiler {
+//		c := jsonschema.NewCompiler()
+//		c.Extensions["powerOf"] = powerOfExt
+//		return c
+//	}}
+//	report, err := r.Run(ctx, "testdata/keywords")
+//
+// Runner.RunFixtures runs fixtures of schemas instead: directories with a
+// schema and instances which must be valid or invalid, see Manifest.
+package testsuite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/cacheloader"
+)
+
+// RemotesURL is the url, at which the test-suite expects its remotes
+// directory to be served.
+const RemotesURL = "http://localhost:1234/"
+
+// Runner runs test-suite files and fixtures.
+type Runner struct {
+	// NewCompiler returns the compiler, to which the schema of a group is
+	// added. It is called for each group and for each directory of fixtures.
+	// If nil, jsonschema.NewCompiler is used.
+	NewCompiler func() *jsonschema.Compiler
+
+	// Remotes is the directory served at RemotesURL, such as the remotes
+	// directory of the test-suite. Urls of other hosts are loaded by the
+	// compiler's loader. Ignored, if empty.
+	Remotes string
+}
+
+// Report is the result of running the files in a directory.
+type Report struct {
+	Files []*FileResult
+}
+
+// Failed tells whether any test of the report failed.
+func (r *Report) Failed() bool {
+	for _, f := range r.Files {
+		if f.Failed() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteTo writes a pass/fail line per file to w, followed by the failed
+// tests of the file.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	for _, f := range r.Files {
+		status := "PASS"
+		if f.Failed() > 0 {
+			status = "FAIL"
+		}
+		fmt.Fprintf(&buf, "%s %s (passed %d, failed %d, skipped %d)\n", status, f.Path, f.Passed(), f.Failed(), f.Skipped())
+		for _, g := range f.Groups {
+			if g.Err != nil {
+				fmt.Fprintf(&buf, "  %s: %v\n", g.Description, g.Err)
+				continue
+			}
+			for _, t := range g.Tests {
+				switch {
+				case t.Skipped || t.Passed():
+				case t.Mismatch != "":
+					fmt.Fprintf(&buf, "  %s: %s: %s\n", g.Description, t.Description, t.Mismatch)
+				default:
+					fmt.Fprintf(&buf, "  %s: %s: expected valid=%t, got valid=%t\n", g.Description, t.Description, t.Valid, t.Err == nil)
+				}
+			}
+		}
+	}
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// FileResult is the result of a test-suite file.
+type FileResult struct {
+	Path   string
+	Groups []*GroupResult
+}
+
+// Passed returns the number of passed tests.
+func (f *FileResult) Passed() int {
+	return f.count(func(g *GroupResult, t *TestResult) bool { return g.Err == nil && !t.Skipped && t.Passed() })
+}
+
+// Failed returns the number of failed tests. Tests of groups, whose schema
+// failed to compile, count as failed.
+func (f *FileResult) Failed() int {
+	return f.count(func(g *GroupResult, t *TestResult) bool { return g.Err != nil || !t.Skipped && !t.Passed() })
+}
+
+// Skipped returns the number of skipped tests.
+func (f *FileResult) Skipped() int {
+	return f.count(func(g *GroupResult, t *TestResult) bool { return g.Err == nil && t.Skipped })
+}
+
+func (f *FileResult) count(match func(g *GroupResult, t *TestResult) bool) int {
+	n := 0
+	for _, g := range f.Groups {
+		for _, t := range g.Tests {
+			if match(g, t) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// GroupResult is the result of a group of tests sharing a schema.
+type GroupResult struct {
+	Description string
+
+	// Err is the error, if the schema failed to compile. Tests are not run
+	// in that case.
+	Err error
+
+	Tests []*TestResult
+}
+
+// TestResult is the result of validating a single instance.
+type TestResult struct {
+	Description string
+
+	// Valid is the expected validity.
+	Valid bool
+
+	// Skipped is set for tests with "skip" property.
+	Skipped bool
+
+	// Err is the validation error, nil if the instance is valid.
+	Err error
+
+	// Mismatch describes how Err differs from the expected error, for
+	// fixtures expecting the error at specific location.
+	Mismatch string
+}
+
+// Passed tells whether the instance was valid as expected.
+func (t *TestResult) Passed() bool {
+	return t.Valid == (t.Err == nil) && t.Mismatch == ""
+}
+
+type group struct {
+	Description string
+	Schema      json.RawMessage
+	Tests       []struct {
+		Description string
+		Data        json.RawMessage
+		Valid       bool
+		Skip        *string
+	}
+}
+
+// Run runs all ".json" files in dir and its subdirectories, in lexical
+// order. The returned error is about reading or parsing the files, failed
+// tests are reported by Report.
+func (r *Runner) Run(ctx context.Context, dir string) (*Report, error) {
+	report := &Report{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		f, err := r.RunFile(ctx, path)
+		if err != nil {
+			return err
+		}
+		report.Files = append(report.Files, f)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// RunFile runs the test-suite file at path.
+func (r *Runner) RunFile(ctx context.Context, path string) (*FileResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var groups []group
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return nil, fmt.Errorf("testsuite: invalid file %s: %w", path, err)
+	}
+
+	result := &FileResult{Path: path}
+	for _, g := range groups {
+		gr := &GroupResult{Description: g.Description}
+		result.Groups = append(result.Groups, gr)
+		for _, t := range g.Tests {
+			gr.Tests = append(gr.Tests, &TestResult{Description: t.Description, Valid: t.Valid, Skipped: t.Skip != nil})
+		}
+
+		schema, err := r.compile(ctx, g.Schema)
+		if err != nil {
+			gr.Err = err
+			continue
+		}
+		for i, t := range g.Tests {
+			if t.Skip == nil {
+				gr.Tests[i].Err = schema.Validate(bytes.NewReader(t.Data))
+			}
+		}
+	}
+	return result, nil
+}
+
+func (r *Runner) compile(ctx context.Context, schema json.RawMessage) (*jsonschema.Schema, error) {
+	c := r.newCompiler()
+	if err := c.AddResource("test.json", bytes.NewReader(schema)); err != nil {
+		return nil, err
+	}
+	return c.Compile(ctx, "test.json")
+}
+
+func (r *Runner) newCompiler() *jsonschema.Compiler {
+	c := jsonschema.NewCompiler()
+	if r.NewCompiler != nil {
+		c = r.NewCompiler()
+	}
+	if r.Remotes != "" {
+		mirror := &cacheloader.Mirror{
+			Prefixes: map[string]string{RemotesURL: r.Remotes},
+			Next:     c.LoadURL,
+		}
+		if mirror.Next == nil {
+			mirror.Next = jsonschema.LoadURL
+		}
+		c.LoadURL = mirror.Load
+	}
+	return c
+}
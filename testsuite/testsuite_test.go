@@ -0,0 +1,145 @@
+package testsuite_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/testsuite"
+)
+
+func evenExt() jsonschema.Extension {
+	meta, err := jsonschema.CompileString(context.Background(), "even.json", `{"properties": {"even": {"type": "boolean"}}}`)
+	if err != nil {
+		panic(err)
+	}
+	return jsonschema.Extension{
+		Meta: meta,
+		Compile: func(ctx jsonschema.CompilerContext, m map[string]interface{}) (interface{}, error) {
+			if even, ok := m["even"]; ok && even.(bool) {
+				return true, nil
+			}
+			return nil, nil
+		},
+		Validate: func(ctx jsonschema.ValidationContext, s interface{}, v interface{}) error {
+			n, ok := v.(json.Number)
+			if !ok {
+				return nil
+			}
+			if i, err := n.Int64(); err != nil || i%2 != 0 {
+				return ctx.Error("even", "%v is not even", v)
+			}
+			return nil
+		},
+	}
+}
+
+func TestRunner(t *testing.T) {
+	r := &testsuite.Runner{
+		NewCompiler: func() *jsonschema.Compiler {
+			c := jsonschema.NewCompiler()
+			c.Extensions["even"] = evenExt()
+			return c
+		},
+		Remotes: "../testdata/remotes",
+	}
+	report, err := r.Run(context.Background(), "testdata/keywords")
+	require.NoError(t, err)
+	require.Len(t, report.Files, 1)
+	f := report.Files[0]
+	assert.Equal(t, 4, f.Passed())
+	assert.Equal(t, 1, f.Failed())
+	assert.Equal(t, 1, f.Skipped())
+	assert.True(t, report.Failed())
+
+	var buf bytes.Buffer
+	_, err = report.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, `FAIL testdata/keywords/even.json (passed 4, failed 1, skipped 1)
+  even keyword with remote ref: wrongly expected valid: expected valid=true, got valid=false
+`, buf.String())
+}
+
+func TestRunner_Draft7(t *testing.T) {
+	r := &testsuite.Runner{Remotes: "../testdata/remotes"}
+	report, err := r.Run(context.Background(), "../testdata/draft7")
+	require.NoError(t, err)
+	if report.Failed() {
+		var buf bytes.Buffer
+		_, _ = report.WriteTo(&buf)
+		t.Error(buf.String())
+	}
+}
+
+func TestRunner_RunFixtures(t *testing.T) {
+	report, err := new(testsuite.Runner).RunFixtures(context.Background(), "testdata/fixtures")
+	require.NoError(t, err)
+	require.Len(t, report.Files, 2)
+
+	address, user := report.Files[0], report.Files[1]
+	assert.Equal(t, "testdata/fixtures/user", user.Path)
+	assert.Equal(t, 4, user.Passed())
+	assert.Equal(t, 0, user.Failed())
+
+	assert.Equal(t, "testdata/fixtures/address", address.Path)
+	assert.Equal(t, 2, address.Passed())
+	assert.Equal(t, 1, address.Failed())
+	assert.True(t, report.Failed())
+
+	var buf bytes.Buffer
+	_, err = report.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "FAIL testdata/fixtures/address (passed 2, failed 1, skipped 0)\n  invalid: testdata/fixtures/address/cases/bad-city.json: expected error at I[#/zip] S[#/properties/zip/pattern], got:\n")
+	assert.Contains(t, buf.String(), "PASS testdata/fixtures/user (passed 4, failed 0, skipped 0)\n")
+}
+
+func TestRunner_RunFixturesNoMatch(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"manifest.json":    `{"schema": "schema.json", "valid": ["valid/*.json"], "invalid": ["invalid/*.jsn"]}`,
+		"schema.json":      `{"type": "string"}`,
+		"valid/a.json":     `"a"`,
+		"invalid/one.json": `1`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644))
+	}
+
+	report, err := new(testsuite.Runner).RunFixtures(context.Background(), dir)
+	require.NoError(t, err)
+	require.Len(t, report.Files, 1)
+	assert.Equal(t, 1, report.Files[0].Passed())
+	assert.Equal(t, 1, report.Files[0].Failed())
+	assert.True(t, report.Failed())
+
+	var buf bytes.Buffer
+	_, err = report.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "  invalid: "+filepath.Join(dir, "invalid/*.jsn")+": no fixtures match the pattern\n")
+}
+
+func TestReadManifest(t *testing.T) {
+	m, ok, err := testsuite.ReadManifest("testdata/fixtures/user")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, testsuite.DefaultManifest, *m)
+
+	m, ok, err = testsuite.ReadManifest("testdata/fixtures/address")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, []testsuite.Expectation{
+		{File: "cases/bad-zip.json", InstancePtr: "#/zip", SchemaPtr: "#/properties/zip/pattern"},
+		{File: "cases/bad-city.json", InstancePtr: "#/zip", SchemaPtr: "#/properties/zip/pattern"},
+	}, m.Invalid)
+
+	_, ok, err = testsuite.ReadManifest("testdata")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,110 @@
+package jsonschema
+
+import "strings"
+
+// Walk calls fn for each error of the tree rooted at ve, in depth-first
+// order, starting with ve. The causes of an error are skipped, if fn
+// returns false for it.
+func (ve *ValidationError) Walk(fn func(err *ValidationError) bool) {
+	if fn(ve) {
+		for _, cause := range ve.Causes {
+			cause.Walk(fn)
+		}
+	}
+}
+
+// Leaves returns the errors of the tree rooted at ve, which have no causes,
+// in depth-first order. These are the errors of the keywords, which failed
+// for a value, rather than the errors grouping them.
+func (ve *ValidationError) Leaves() []*ValidationError {
+	var leaves []*ValidationError
+	ve.Walk(func(err *ValidationError) bool {
+		if len(err.Causes) == 0 {
+			leaves = append(leaves, err)
+		}
+		return true
+	})
+	return leaves
+}
+
+// ByInstancePtr returns the leaf errors of the tree rooted at ve, grouped by
+// their InstancePtr.
+func (ve *ValidationError) ByInstancePtr() map[string][]*ValidationError {
+	m := make(map[string][]*ValidationError)
+	for _, leaf := range ve.Leaves() {
+		m[leaf.InstancePtr] = append(m[leaf.InstancePtr], leaf)
+	}
+	return m
+}
+
+// ByKeyword returns the errors of the tree rooted at ve, which are reported
+// by any of given keywords, such as "required", in depth-first order.
+func (ve *ValidationError) ByKeyword(keywords ...string) []*ValidationError {
+	var errs []*ValidationError
+	ve.Walk(func(err *ValidationError) bool {
+		for _, keyword := range keywords {
+			if err.Keyword == keyword {
+				errs = append(errs, err)
+				break
+			}
+		}
+		return true
+	})
+	return errs
+}
+
+// Unwrap returns the causes of ve, so that errors.Is and errors.As search
+// the whole tree.
+func (ve *ValidationError) Unwrap() []error {
+	errs := make([]error, len(ve.Causes))
+	for i, cause := range ve.Causes {
+		errs[i] = cause
+	}
+	return errs
+}
+
+// Pointers returns the json-pointers of the values ve is about. These are
+// the missing properties for "required" and "dependencies", the unexpected
+// properties for "additionalProperties", and InstancePtr otherwise.
+func (ve *ValidationError) Pointers() []string {
+	var ptrs []string
+	switch c := ve.Context.(type) {
+	case *ValidationErrorContextRequired:
+		ptrs = c.Missing
+	case *ValidationErrorContextAdditionalProperties:
+		ptrs = c.Properties
+	case *ValidationErrorContextDependencies:
+		ptrs = []string{c.Missing}
+	}
+	if len(ptrs) == 0 {
+		return []string{ve.InstancePtr}
+	}
+	return ptrs
+}
+
+// SplitPointer returns the unescaped tokens of json-pointer ptr, in the URI
+// fragment form used by InstancePtr, such as "#/address/a~1b". The pointer
+// to the document is split to no tokens.
+func SplitPointer(ptr string) []string {
+	ptr = strings.TrimPrefix(strings.TrimPrefix(ptr, "#"), "/")
+	if ptr == "" {
+		return nil
+	}
+	tokens := strings.Split(ptr, "/")
+	for i, token := range tokens {
+		tokens[i] = unescape(token)
+	}
+	return tokens
+}
+
+// JoinPointer is the inverse of SplitPointer. It returns the json-pointer in
+// URI fragment form to the value at given tokens.
+func JoinPointer(tokens []string) string {
+	var b strings.Builder
+	b.WriteString("#")
+	for _, token := range tokens {
+		b.WriteString("/")
+		b.WriteString(escape(token))
+	}
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/jsonschematest"
+)
+
+func TestTraverse(t *testing.T) {
+	schema, err := jsonschema.CompileString(ctx, "test.json", `{
+		"required": ["id"],
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "pattern": "^[a-z]+$"},
+			"tags": {"items": {"type": "string"}}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verr := jsonschematest.AssertInvalid(t, schema, `{"name": "A", "tags": ["a", 1]}`)
+	if verr == nil {
+		t.FailNow()
+	}
+
+	var visited int
+	verr.Walk(func(err *jsonschema.ValidationError) bool {
+		visited++
+		return err.InstancePtr != "#/name"
+	})
+	if visited != 4 {
+		t.Errorf("expected 4 visited errors, got %d", visited)
+	}
+
+	leaves := verr.Leaves()
+	if len(leaves) != 4 {
+		t.Errorf("expected 4 leaves, got %d", len(leaves))
+	}
+	for _, leaf := range leaves {
+		if len(leaf.Causes) != 0 {
+			t.Errorf("leaf %s has causes", leaf.MessageFmt())
+		}
+	}
+
+	got := make(map[string]int)
+	for ptr, errs := range verr.ByInstancePtr() {
+		got[ptr] = len(errs)
+	}
+	if fmt.Sprint(got) != "map[#:1 #/name:2 #/tags/1:1]" {
+		t.Errorf("unexpected groups %v", got)
+	}
+
+	if errs := verr.ByKeyword("type", "required"); len(errs) != 2 {
+		t.Errorf("expected 2 errors, got %d", len(errs))
+	}
+	if errs := verr.ByKeyword("maxLength"); len(errs) != 0 {
+		t.Errorf("expected no errors, got %d", len(errs))
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	schema, err := jsonschema.CompileString(ctx, "test.json", `{"properties": {"a": {"minimum": 1}, "b": {"maximum": 1}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = schema.ValidateInterface(decode(t, `{"a": 0, "b": 2}`))
+	if err == nil {
+		t.Fatal("validation must fail")
+	}
+	leaf := err.(*jsonschema.ValidationError).ByKeyword("maximum")[0]
+	if !errors.Is(err, leaf) {
+		t.Error("errors.Is must find the cause")
+	}
+
+	var found *jsonschema.ValidationError
+	if !errors.As(err, &found) || found != err {
+		t.Error("errors.As must find the root first")
+	}
+	if len(err.(*jsonschema.ValidationError).Unwrap()) != 2 {
+		t.Error("expected 2 causes")
+	}
+}
+
+func TestPointers(t *testing.T) {
+	schema, err := jsonschema.CompileString(ctx, "test.json", `{
+		"required": ["a/b"],
+		"additionalProperties": false,
+		"properties": {"c": {"type": "string"}}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = schema.ValidateInterface(decode(t, `{"c": 1, "d%": 2}`))
+	if err == nil {
+		t.Fatal("validation must fail")
+	}
+	var got []string
+	for _, leaf := range err.(*jsonschema.ValidationError).Leaves() {
+		for _, ptr := range leaf.Pointers() {
+			got = append(got, fmt.Sprintf("%q", jsonschema.SplitPointer(ptr)))
+			if p := jsonschema.JoinPointer(jsonschema.SplitPointer(ptr)); p != ptr {
+				t.Errorf("JoinPointer(SplitPointer(%q)) = %q", ptr, p)
+			}
+		}
+	}
+	if want := `["a/b"] ["c"] ["d%"]`; strings.Join(got, " ") != want {
+		t.Errorf("got %s, want %s", strings.Join(got, " "), want)
+	}
+}
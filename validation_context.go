@@ -1,10 +1,6 @@
 package jsonschema
 
-import (
-	"fmt"
-	"strconv"
-	"strings"
-)
+import "math/big"
 
 // ValidationErrorContext
 type ValidationErrorContext interface {
@@ -19,32 +15,277 @@ type ValidationErrorContextRequired struct {
 }
 
 func (r *ValidationErrorContextRequired) AddContext(instancePtr, _ string) {
-	for k, p := range r.Missing {
-		r.Missing[k] = joinPtr(instancePtr, p)
-	}
+	addPtrsContext(instancePtr, r.Missing)
 }
 
 func (r *ValidationErrorContextRequired) FinishInstanceContext() {
-	for k, p := range r.Missing {
+	finishPtrsContext(r.Missing)
+}
+
+func addPtrsContext(instancePtr string, ptrs []string) {
+	for k, p := range ptrs {
+		ptrs[k] = joinPtr(instancePtr, p)
+	}
+}
+
+func finishPtrsContext(ptrs []string) {
+	for k, p := range ptrs {
 		if len(p) == 0 {
-			r.Missing[k] = "#"
+			ptrs[k] = "#"
 		} else {
-			r.Missing[k] = "#/" + p
+			ptrs[k] = "#/" + p
 		}
 	}
 }
 
-func validationRequiredError(properties []string) *ValidationError {
-	missing := make([]string, len(properties))
+// noPtrs implements ValidationErrorContext for contexts without json-pointers.
+type noPtrs struct{}
+
+func (noPtrs) AddContext(_, _ string) {}
+
+func (noPtrs) FinishInstanceContext() {}
+
+// ValidationErrorContextRef is used as error context when the schema referenced by "$ref" fails.
+type ValidationErrorContextRef struct {
+	noPtrs
+	// Ref is the url of the referenced schema.
+	Ref string
+}
+
+// ValidationErrorContextType is used as error context when the type of the value is not allowed.
+type ValidationErrorContextType struct {
+	noPtrs
+	// Expected contains the allowed types.
+	Expected []string
+	// Actual is the type of the value.
+	Actual string
+}
+
+// ValidationErrorContextConst is used as error context when the value is not the constant.
+type ValidationErrorContextConst struct {
+	noPtrs
+	Expected interface{}
+	Actual   interface{}
+}
+
+// ValidationErrorContextEnum is used as error context when the value is not in the enum.
+type ValidationErrorContextEnum struct {
+	noPtrs
+	Expected []interface{}
+	Actual   interface{}
+}
+
+// ValidationErrorContextFormat is used as error context when the value is not valid format.
+type ValidationErrorContextFormat struct {
+	noPtrs
+	Format string
+	Actual interface{}
+}
+
+// ValidationErrorContextNot is used as error context when the value is valid against "not" schema.
+type ValidationErrorContextNot struct {
+	noPtrs
+}
+
+// ValidationErrorContextAllOf is used as error context when the value is not valid against a schema of "allOf".
+type ValidationErrorContextAllOf struct {
+	noPtrs
+	// Index is the index of the schema in "allOf".
+	Index int
+}
+
+// ValidationErrorContextAnyOf is used as error context when the value is valid against none of "anyOf".
+type ValidationErrorContextAnyOf struct {
+	noPtrs
+}
+
+// ValidationErrorContextOneOf is used as error context when the value is not valid against exactly one of "oneOf".
+type ValidationErrorContextOneOf struct {
+	noPtrs
+	// Matched contains the indexes of two schemas, the value is valid against.
+	// It is nil, if the value is valid against none.
+	Matched []int
+}
+
+// ValidationErrorContextThen is used as error context when the value is valid against "if", but not against "then".
+type ValidationErrorContextThen struct {
+	noPtrs
+}
+
+// ValidationErrorContextElse is used as error context when the value is not valid against "if" and "else".
+type ValidationErrorContextElse struct {
+	noPtrs
+}
+
+// ValidationErrorContextReadOnly is used as error context when a read-only value is in a request.
+type ValidationErrorContextReadOnly struct {
+	noPtrs
+}
+
+// ValidationErrorContextWriteOnly is used as error context when a write-only value is in a response.
+type ValidationErrorContextWriteOnly struct {
+	noPtrs
+}
+
+// ValidationErrorContextMinProperties is used as error context when the object has too few properties.
+type ValidationErrorContextMinProperties struct {
+	noPtrs
+	Expected int
+	Actual   int
+}
+
+// ValidationErrorContextMaxProperties is used as error context when the object has too many properties.
+type ValidationErrorContextMaxProperties struct {
+	noPtrs
+	Expected int
+	Actual   int
+}
+
+// ValidationErrorContextAdditionalProperties is used as error context when the object has properties, which are not allowed.
+type ValidationErrorContextAdditionalProperties struct {
+	// Properties contains JSON Pointers to all properties not allowed.
+	Properties []string
+}
+
+func (r *ValidationErrorContextAdditionalProperties) AddContext(instancePtr, _ string) {
+	addPtrsContext(instancePtr, r.Properties)
+}
 
-	for k := range missing {
-		missing[k] = strconv.Quote(properties[k])
+func (r *ValidationErrorContextAdditionalProperties) FinishInstanceContext() {
+	finishPtrsContext(r.Properties)
+}
+
+// ValidationErrorContextDependencies is used as error context when a property required by a present property is missing.
+type ValidationErrorContextDependencies struct {
+	// Property is the name of the present property.
+	Property string
+	// Missing contains JSON Pointer to the missing property.
+	Missing string
+}
+
+func (r *ValidationErrorContextDependencies) AddContext(instancePtr, _ string) {
+	r.Missing = joinPtr(instancePtr, r.Missing)
+}
+
+func (r *ValidationErrorContextDependencies) FinishInstanceContext() {
+	ptrs := []string{r.Missing}
+	finishPtrsContext(ptrs)
+	r.Missing = ptrs[0]
+}
+
+// ValidationErrorContextMinItems is used as error context when the array has too few items.
+type ValidationErrorContextMinItems struct {
+	noPtrs
+	Expected int
+	Actual   int
+}
+
+// ValidationErrorContextMaxItems is used as error context when the array has too many items.
+type ValidationErrorContextMaxItems struct {
+	noPtrs
+	Expected int
+	Actual   int
+}
+
+// ValidationErrorContextUniqueItems is used as error context when the array has equal items.
+type ValidationErrorContextUniqueItems struct {
+	noPtrs
+	// Indexes contains the indexes of the equal items.
+	Indexes [2]int
+}
+
+// ValidationErrorContextAdditionalItems is used as error context when the array has more items than "items" allows.
+type ValidationErrorContextAdditionalItems struct {
+	noPtrs
+	Expected int
+	Actual   int
+}
+
+// ValidationErrorContextContains is used as error context when the array has no item valid against "contains".
+type ValidationErrorContextContains struct {
+	noPtrs
+}
+
+// ValidationErrorContextMinLength is used as error context when the string is too short.
+type ValidationErrorContextMinLength struct {
+	noPtrs
+	Expected int
+	Actual   int
+}
+
+// ValidationErrorContextMaxLength is used as error context when the string is too long.
+type ValidationErrorContextMaxLength struct {
+	noPtrs
+	Expected int
+	Actual   int
+}
+
+// ValidationErrorContextRegexProperties is used as error context when a property name is not a valid regex.
+type ValidationErrorContextRegexProperties struct {
+	noPtrs
+	Property string
+}
+
+// ValidationErrorContextPattern is used as error context when the string does not match the pattern.
+type ValidationErrorContextPattern struct {
+	noPtrs
+	Pattern string
+	Actual  string
+}
+
+// ValidationErrorContextContentEncoding is used as error context when the string cannot be decoded.
+type ValidationErrorContextContentEncoding struct {
+	noPtrs
+	Encoding string
+	Actual   string
+}
+
+// ValidationErrorContextContentMediaType is used as error context when the content is not of the media type.
+type ValidationErrorContextContentMediaType struct {
+	noPtrs
+	MediaType string
+}
+
+// ValidationErrorContextMinimum is used as error context when the number is less than "minimum".
+type ValidationErrorContextMinimum struct {
+	noPtrs
+	Expected *big.Float
+	Actual   interface{}
+}
+
+// ValidationErrorContextExclusiveMinimum is used as error context when the number is not greater than "exclusiveMinimum".
+type ValidationErrorContextExclusiveMinimum struct {
+	noPtrs
+	Expected *big.Float
+	Actual   interface{}
+}
+
+// ValidationErrorContextMaximum is used as error context when the number is greater than "maximum".
+type ValidationErrorContextMaximum struct {
+	noPtrs
+	Expected *big.Float
+	Actual   interface{}
+}
+
+// ValidationErrorContextExclusiveMaximum is used as error context when the number is not less than "exclusiveMaximum".
+type ValidationErrorContextExclusiveMaximum struct {
+	noPtrs
+	Expected *big.Float
+	Actual   interface{}
+}
+
+// ValidationErrorContextMultipleOf is used as error context when the number is not a multiple of "multipleOf".
+type ValidationErrorContextMultipleOf struct {
+	noPtrs
+	Expected *big.Float
+	Actual   interface{}
+}
+
+func validationRequiredError(properties []string) *ValidationError {
+	for k := range properties {
 		properties[k] = escape(properties[k])
 	}
 
-	return &ValidationError{
-		SchemaPtr: "required",
-		Message:   fmt.Sprintf("missing properties: %s", strings.Join(missing, ", ")),
-		Context:   &ValidationErrorContextRequired{Missing: properties},
-	}
+	return keywordError("required").
+		withContext(&ValidationErrorContextRequired{Missing: properties})
 }
@@ -3,11 +3,13 @@ package jsonschema_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
 
 	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/jsonschematest"
 )
 
 func TestErrorsContext(t *testing.T) {
@@ -86,3 +88,123 @@ func TestErrorsContext(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorsKeywordContext(t *testing.T) {
+	for k, tc := range []struct {
+		schema   string
+		doc      string
+		keyword  string
+		expected jsonschema.ValidationErrorContext
+	}{
+		{
+			schema:   `{"type": ["string", "null"]}`,
+			doc:      `1`,
+			keyword:  "type",
+			expected: &jsonschema.ValidationErrorContextType{Expected: []string{"string", "null"}, Actual: "number"},
+		},
+		{
+			schema:   `{"enum": ["a", "b"]}`,
+			doc:      `"c"`,
+			keyword:  "enum",
+			expected: &jsonschema.ValidationErrorContextEnum{Expected: []interface{}{"a", "b"}, Actual: "c"},
+		},
+		{
+			schema:   `{"format": "email"}`,
+			doc:      `"alice"`,
+			keyword:  "format",
+			expected: &jsonschema.ValidationErrorContextFormat{Format: "email", Actual: "alice"},
+		},
+		{
+			schema:   `{"oneOf": [{"type": "string"}, {"minLength": 1}]}`,
+			doc:      `"alice"`,
+			keyword:  "oneOf",
+			expected: &jsonschema.ValidationErrorContextOneOf{Matched: []int{0, 1}},
+		},
+		{
+			schema:   `{"maxProperties": 1}`,
+			doc:      `{"a": 1, "b": 2}`,
+			keyword:  "maxProperties",
+			expected: &jsonschema.ValidationErrorContextMaxProperties{Expected: 1, Actual: 2},
+		},
+		{
+			schema:   `{"properties": {"a": {"additionalProperties": false}}}`,
+			doc:      `{"a": {"b/c": 1}}`,
+			keyword:  "additionalProperties",
+			expected: &jsonschema.ValidationErrorContextAdditionalProperties{Properties: []string{"#/a/b~1c"}},
+		},
+		{
+			schema:   `{"properties": {"a": {"dependencies": {"card": ["billing"]}}}}`,
+			doc:      `{"a": {"card": 1}}`,
+			keyword:  "dependencies",
+			expected: &jsonschema.ValidationErrorContextDependencies{Property: "card", Missing: "#/a/billing"},
+		},
+		{
+			schema:   `{"uniqueItems": true}`,
+			doc:      `[1, 2, 1]`,
+			keyword:  "uniqueItems",
+			expected: &jsonschema.ValidationErrorContextUniqueItems{Indexes: [2]int{0, 2}},
+		},
+		{
+			schema:   `{"items": [{}], "additionalItems": false}`,
+			doc:      `[1, 2]`,
+			keyword:  "additionalItems",
+			expected: &jsonschema.ValidationErrorContextAdditionalItems{Expected: 1, Actual: 2},
+		},
+		{
+			schema:   `{"minLength": 3}`,
+			doc:      `"ab"`,
+			keyword:  "minLength",
+			expected: &jsonschema.ValidationErrorContextMinLength{Expected: 3, Actual: 2},
+		},
+		{
+			schema:   `{"pattern": "^[a-z]+$"}`,
+			doc:      `"A1"`,
+			keyword:  "pattern",
+			expected: &jsonschema.ValidationErrorContextPattern{Pattern: "^[a-z]+$", Actual: "A1"},
+		},
+		{
+			schema:   `{"$ref": "#/definitions/a", "definitions": {"a": {"not": {}}}}`,
+			doc:      `1`,
+			keyword:  "$ref",
+			expected: &jsonschema.ValidationErrorContextRef{Ref: "test.json#/definitions/a"},
+		},
+	} {
+		t.Run(fmt.Sprintf("case=%d", k), func(t *testing.T) {
+			schema, err := jsonschema.CompileString(ctx, "test.json", tc.schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			verr := jsonschematest.AssertInvalid(t, schema, tc.doc)
+			if verr == nil {
+				t.FailNow()
+			}
+			for verr.Keyword == "" && len(verr.Causes) == 1 {
+				verr = verr.Causes[0]
+			}
+			if verr.Keyword != tc.keyword {
+				t.Errorf("expected keyword %q, got %q", tc.keyword, verr.Keyword)
+			}
+			if !reflect.DeepEqual(tc.expected, verr.Context) {
+				t.Errorf("expected:\t%#v\n\tactual:\t%#v", tc.expected, verr.Context)
+			}
+		})
+	}
+}
+
+func TestErrorsNumberContext(t *testing.T) {
+	schema, err := jsonschema.CompileString(ctx, "test.json", `{"minimum": 2.5}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verr := jsonschematest.AssertInvalid(t, schema, `1`)
+	if verr == nil {
+		t.FailNow()
+	}
+	c, ok := verr.Context.(*jsonschema.ValidationErrorContextMinimum)
+	if !ok {
+		t.Fatalf("expected *ValidationErrorContextMinimum, got %T", verr.Context)
+	}
+	if c.Expected.String() != "2.5" || c.Actual != json.Number("1") {
+		t.Errorf("expected 2.5 and 1, got %v and %v", c.Expected, c.Actual)
+	}
+}
@@ -0,0 +1,93 @@
+// Package wellknown bundles commonly referenced meta-schemas and vocabularies,
+// so that "$ref"s to them resolve without network.
+//
+// The json-schema meta-schemas of draft4, draft6 and draft7 are always available
+// to a Compiler. This package adds their hyper-schemas and link description
+// objects, the meta-schemas and vocabularies of draft 2019-09 and 2020-12, the
+// OpenAPI 3.0 schema and the OpenAPI 3.1 Schema Object dialect.
+//
+// To use wellknown, register the schemas with your compiler:
+//
+//	c := jsonschema.NewCompiler()
+//	if err := wellknown.Register(c); err != nil {
+//		return err
+//	}
+//
+// Note that this package compiles only draft4, draft6 and draft7 schemas. The
+// schemas of later drafts are bundled, so that tools working on the documents
+// such as the bundler can resolve them, but compiling them fails.
+package wellknown
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+//go:embed schemas
+var schemas embed.FS
+
+// files maps the url of each bundled schema to its file in schemas.
+var files = map[string]string{
+	"http://json-schema.org/draft-04/hyper-schema": "json-schema.org/draft-04/hyper-schema.json",
+	"http://json-schema.org/draft-06/hyper-schema": "json-schema.org/draft-06/hyper-schema.json",
+	"http://json-schema.org/draft-06/links":        "json-schema.org/draft-06/links.json",
+	"http://json-schema.org/draft-07/hyper-schema": "json-schema.org/draft-07/hyper-schema.json",
+	"http://json-schema.org/draft-07/links":        "json-schema.org/draft-07/links.json",
+
+	"https://json-schema.org/draft/2019-09/schema":            "json-schema.org/draft/2019-09/schema.json",
+	"https://json-schema.org/draft/2019-09/meta/core":         "json-schema.org/draft/2019-09/meta/core.json",
+	"https://json-schema.org/draft/2019-09/meta/applicator":   "json-schema.org/draft/2019-09/meta/applicator.json",
+	"https://json-schema.org/draft/2019-09/meta/validation":   "json-schema.org/draft/2019-09/meta/validation.json",
+	"https://json-schema.org/draft/2019-09/meta/meta-data":    "json-schema.org/draft/2019-09/meta/meta-data.json",
+	"https://json-schema.org/draft/2019-09/meta/format":       "json-schema.org/draft/2019-09/meta/format.json",
+	"https://json-schema.org/draft/2019-09/meta/content":      "json-schema.org/draft/2019-09/meta/content.json",
+	"https://json-schema.org/draft/2019-09/meta/hyper-schema": "json-schema.org/draft/2019-09/meta/hyper-schema.json",
+	"https://json-schema.org/draft/2019-09/hyper-schema":      "json-schema.org/draft/2019-09/hyper-schema.json",
+	"https://json-schema.org/draft/2019-09/links":             "json-schema.org/draft/2019-09/links.json",
+
+	"https://json-schema.org/draft/2020-12/schema":                 "json-schema.org/draft/2020-12/schema.json",
+	"https://json-schema.org/draft/2020-12/meta/core":              "json-schema.org/draft/2020-12/meta/core.json",
+	"https://json-schema.org/draft/2020-12/meta/applicator":        "json-schema.org/draft/2020-12/meta/applicator.json",
+	"https://json-schema.org/draft/2020-12/meta/unevaluated":       "json-schema.org/draft/2020-12/meta/unevaluated.json",
+	"https://json-schema.org/draft/2020-12/meta/validation":        "json-schema.org/draft/2020-12/meta/validation.json",
+	"https://json-schema.org/draft/2020-12/meta/meta-data":         "json-schema.org/draft/2020-12/meta/meta-data.json",
+	"https://json-schema.org/draft/2020-12/meta/format-annotation": "json-schema.org/draft/2020-12/meta/format-annotation.json",
+	"https://json-schema.org/draft/2020-12/meta/format-assertion":  "json-schema.org/draft/2020-12/meta/format-assertion.json",
+	"https://json-schema.org/draft/2020-12/meta/content":           "json-schema.org/draft/2020-12/meta/content.json",
+	"https://json-schema.org/draft/2020-12/meta/hyper-schema":      "json-schema.org/draft/2020-12/meta/hyper-schema.json",
+	"https://json-schema.org/draft/2020-12/hyper-schema":           "json-schema.org/draft/2020-12/hyper-schema.json",
+	"https://json-schema.org/draft/2020-12/links":                  "json-schema.org/draft/2020-12/links.json",
+
+	"https://spec.openapis.org/oas/3.0/schema/2021-09-28": "spec.openapis.org/oas/3.0/schema/2021-09-28.json",
+	"https://spec.openapis.org/oas/3.1/dialect/base":      "spec.openapis.org/oas/3.1/dialect/base.json",
+	"https://spec.openapis.org/oas/3.1/meta/base":         "spec.openapis.org/oas/3.1/meta/base.json",
+}
+
+// URLs returns the sorted urls of the bundled schemas.
+func URLs() []string {
+	urls := make([]string, 0, len(files))
+	for url := range files {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+// Register adds all bundled schemas to the compiler c using Compiler.AddResource.
+func Register(c *jsonschema.Compiler) error {
+	for _, url := range URLs() {
+		f, err := schemas.Open("schemas/" + files[url])
+		if err != nil {
+			return fmt.Errorf("could not open bundled schema %s: %w", url, err)
+		}
+		err = c.AddResource(url, f)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package wellknown_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/jsonschema/v3"
+	"github.com/ory/jsonschema/v3/wellknown"
+)
+
+func newCompiler(t *testing.T) *jsonschema.Compiler {
+	c := jsonschema.NewCompiler()
+	c.LoadURL = func(_ context.Context, url string) (io.ReadCloser, error) {
+		return nil, errors.New("network access is not allowed: " + url)
+	}
+	require.NoError(t, wellknown.Register(c))
+	return c
+}
+
+func TestRegister(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("case=bundled schemas are valid json", func(t *testing.T) {
+		assert.Len(t, wellknown.URLs(), 30)
+		newCompiler(t)
+	})
+
+	t.Run("case=hyper-schemas compile", func(t *testing.T) {
+		for _, url := range []string{
+			"http://json-schema.org/draft-04/hyper-schema#",
+			"http://json-schema.org/draft-06/hyper-schema#",
+			"http://json-schema.org/draft-07/hyper-schema#",
+			"http://json-schema.org/draft-07/links#",
+		} {
+			_, err := newCompiler(t).Compile(ctx, url)
+			assert.NoError(t, err, url)
+		}
+	})
+
+	t.Run("case=openapi 3.0", func(t *testing.T) {
+		c := newCompiler(t)
+		require.NoError(t, c.AddResource("user.json", strings.NewReader(`{
+			"$ref": "https://spec.openapis.org/oas/3.0/schema/2021-09-28#/definitions/Schema"
+		}`)))
+		s, err := c.Compile(ctx, "user.json")
+		require.NoError(t, err)
+		assert.NoError(t, s.Validate(strings.NewReader(`{"type": "string", "nullable": true}`)))
+		assert.Error(t, s.Validate(strings.NewReader(`{"type": "null"}`)))
+
+		s, err = c.Compile(ctx, "https://spec.openapis.org/oas/3.0/schema/2021-09-28")
+		require.NoError(t, err)
+		assert.NoError(t, s.Validate(strings.NewReader(`{
+			"openapi": "3.0.3",
+			"info": {"title": "Identities", "version": "1.0.0"},
+			"paths": {
+				"/identities/{id}": {
+					"get": {
+						"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+						"responses": {"200": {"description": "ok"}}
+					}
+				}
+			}
+		}`)))
+		assert.Error(t, s.Validate(strings.NewReader(`{"openapi": "3.1.0", "info": {}, "paths": {}}`)))
+	})
+}